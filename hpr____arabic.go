@@ -0,0 +1,45 @@
+package filter
+
+import "strings"
+
+// arabic_folds are the replacements of normalize_arabic (in order),
+// the letters are folded to their base form and the marks are removed
+var arabic_folds = [][2]string{
+	// alef with hamza or madda, and alef wasla
+	{"أ", "ا"}, {"إ", "ا"}, {"آ", "ا"}, {"ٱ", "ا"},
+	// taa marbuta and alef maqsura
+	{"ة", "ه"}, {"ى", "ي"},
+	// tashkeel (fathatan .. sukun) and superscript alef
+	{"ً", ""}, {"ٌ", ""}, {"ٍ", ""}, {"َ", ""},
+	{"ُ", ""}, {"ِ", ""}, {"ّ", ""}, {"ْ", ""},
+	{"ٰ", ""},
+	// tatweel
+	{"ـ", ""},
+}
+
+var arabic_normalizer = func() *strings.Replacer {
+	var pairs = make([]string, 0, 2*len(arabic_folds))
+	for _, fold := range arabic_folds {
+		pairs = append(pairs, fold[0], fold[1])
+	}
+	return strings.NewReplacer(pairs...)
+}()
+
+// normalize_arabic folds the variants of alef, taa marbuta and alef maqsura
+// and removes the tashkeel and the tatweel: أَحْمَـد => احمد
+func normalize_arabic(s string) string {
+	if !has_non_ascii(s) {
+		return s
+	}
+	return arabic_normalizer.Replace(s)
+}
+
+// normalize_arabic_sql returns the SQL expression that applies normalize_arabic to col
+// (nested REPLACE calls, which are supported by all the dialects)
+func normalize_arabic_sql(col string, d Dialect) string {
+	var expr = col
+	for _, fold := range arabic_folds {
+		expr = "REPLACE(" + expr + "," + d.QuoteString(fold[0]) + "," + d.QuoteString(fold[1]) + ")"
+	}
+	return expr
+}
@@ -0,0 +1,83 @@
+package filter
+
+import (
+	"net/url"
+	"strings"
+)
+
+// the comparison operator of each date operator
+var date_ops = map[string]string{
+	"eq":  "=",
+	"pr":  "<",
+	"pre": "<=",
+	"ps":  ">",
+	"pse": ">=",
+}
+
+// construct_date_range returns the condition of (eq) if it exists,
+// otherwise the conditions of (pr or pre) and (ps or pse) joined with AND,
+// construct returns the condition of a single operator
+func construct_date_range(
+	v url.Values,
+	key string,
+	construct func(op string, input string) (string, error),
+) (string, error) {
+
+	if input, ok := get_first_el_if_exists(v, key+"[eq]"); ok {
+		return construct("eq", input)
+	}
+
+	var conds = []string{}
+
+	for _, ops := range [][]string{{"pr", "pre"}, {"ps", "pse"}} {
+		for _, op := range ops {
+			var input, ok = get_first_el_if_exists(v, key+"["+op+"]")
+			if !ok {
+				continue
+			}
+
+			var cond, err = construct(op, input)
+			if err != nil {
+				return "", err
+			}
+
+			conds = append(conds, cond)
+			break
+		}
+	}
+
+	switch len(conds) {
+	case 0:
+		return "", nil
+	case 1:
+		return conds[0], nil
+	}
+
+	return "(" + strings.Join(conds, " AND ") + ")", nil
+}
+
+// with_null_cond applies the ([null]) operator to cond:
+// [null]=0 => col IS NOT NULL (only if there is not any other condition)
+// [null]=1 => col IS NULL (OR the other condition)
+func with_null_cond(v url.Values, key string, col string, cond string) string {
+	var input, ok = get_first_el_if_exists(v, key+"[null]")
+
+	if !ok {
+		return cond
+	}
+
+	input = normalize_digits(input)
+
+	if cond != "" {
+		if input != "0" {
+			cond = "(" + cond + " OR " + col + " IS NULL)"
+		}
+		return cond
+	}
+
+	if input == "0" {
+		return col + " IS NOT NULL"
+	}
+
+	return col + " IS NULL"
+}
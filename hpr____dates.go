@@ -0,0 +1,128 @@
+package filter
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// ±N days, weeks, months or years from today: -7d, +2w, -1m, +1y
+var relative_date_regexp = regexp.MustCompile(`^([+-])(\d{1,4})([dwmy])$`)
+
+// resolve_relative_date resolves a relative date (today, startOfMonth, -7d, ...),
+// today should be a midnight in the configured zone
+func resolve_relative_date(input string, today time.Time, week_start time.Weekday) (time.Time, bool) {
+	switch input {
+	case "now", "today":
+		return today, true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	case "startOfWeek":
+		return start_of_week(today, week_start), true
+	case "endOfWeek":
+		return start_of_week(today, week_start).AddDate(0, 0, 6), true
+	case "startOfMonth":
+		return start_of_month(today), true
+	case "endOfMonth":
+		return start_of_month(today).AddDate(0, 1, -1), true
+	case "startOfYear":
+		return start_of_year(today), true
+	case "endOfYear":
+		return start_of_year(today).AddDate(1, 0, -1), true
+	}
+
+	var parts = relative_date_regexp.FindStringSubmatch(input)
+
+	if parts == nil {
+		return time.Time{}, false
+	}
+
+	var n, _ = strconv.Atoi(parts[2])
+
+	if parts[1] == "-" {
+		n = -n
+	}
+
+	switch parts[3] {
+	case "d":
+		return today.AddDate(0, 0, n), true
+	case "w":
+		return today.AddDate(0, 0, 7*n), true
+	case "m":
+		return add_months(today, n), true
+	}
+
+	return add_months(today, 12*n), true
+}
+
+// the names of the presets in the order of the error message
+var date_preset_names = []string{
+	"today",
+	"yesterday",
+	"this_week",
+	"last_7_days",
+	"last_30_days",
+	"this_month",
+	"this_year",
+}
+
+// date_presets returns the first and the last day (inclusive) of each preset
+var date_presets = map[string]func(today time.Time, week_start time.Weekday) (time.Time, time.Time){
+	"today": func(today time.Time, _ time.Weekday) (time.Time, time.Time) {
+		return today, today
+	},
+	"yesterday": func(today time.Time, _ time.Weekday) (time.Time, time.Time) {
+		var yesterday = today.AddDate(0, 0, -1)
+		return yesterday, yesterday
+	},
+	"this_week": func(today time.Time, week_start time.Weekday) (time.Time, time.Time) {
+		var start = start_of_week(today, week_start)
+		return start, start.AddDate(0, 0, 6)
+	},
+	"last_7_days": func(today time.Time, _ time.Weekday) (time.Time, time.Time) {
+		return today.AddDate(0, 0, -6), today
+	},
+	"last_30_days": func(today time.Time, _ time.Weekday) (time.Time, time.Time) {
+		return today.AddDate(0, 0, -29), today
+	},
+	"this_month": func(today time.Time, _ time.Weekday) (time.Time, time.Time) {
+		var start = start_of_month(today)
+		return start, start.AddDate(0, 1, -1)
+	},
+	"this_year": func(today time.Time, _ time.Weekday) (time.Time, time.Time) {
+		var start = start_of_year(today)
+		return start, start.AddDate(1, 0, -1)
+	},
+}
+
+// midnight_in returns the midnight of the day of t in loc
+func midnight_in(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// utc_date returns the same day of t at midnight UTC (like time.Parse(DATE_LAYOUT, ..))
+func utc_date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func start_of_week(t time.Time, week_start time.Weekday) time.Time {
+	return t.AddDate(0, 0, -((int(t.Weekday()) - int(week_start) + 7) % 7))
+}
+
+func start_of_month(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+func start_of_year(t time.Time) time.Time {
+	return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+}
+
+// add_months clamps the day to the end of the month: 2024-03-31 -1m => 2024-02-29
+func add_months(t time.Time, n int) time.Time {
+	var first = time.Date(t.Year(), t.Month()+time.Month(n), 1, 0, 0, 0, 0, t.Location())
+	var last_day = first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), last_day)-1)
+}
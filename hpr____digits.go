@@ -0,0 +1,68 @@
+package filter
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// normalize_digits replaces the Eastern Arabic (٠-٩) and the Persian (۰-۹) digits with ASCII digits
+func normalize_digits(s string) string {
+	if !has_non_ascii(s) {
+		return s
+	}
+
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '٠' && r <= '٩':
+			return '0' + (r - '٠')
+		case r >= '۰' && r <= '۹':
+			return '0' + (r - '۰')
+		}
+		return r
+	}, s)
+}
+
+// localize_digits replaces the ASCII digits with the Eastern Arabic digits,
+// except the digits inside words (last_7_days) which are identifiers
+func localize_digits(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for idx, r := range s {
+		if r >= '0' && r <= '9' && !inside_word(s, idx) {
+			b.WriteRune('٠' + (r - '0'))
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// inside_word reports whether the run of digits at idx is attached to an ASCII letter or an underscore
+func inside_word(s string, idx int) bool {
+	var start = idx
+	for start > 0 && s[start-1] >= '0' && s[start-1] <= '9' {
+		start--
+	}
+
+	var end = idx
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+
+	return (start > 0 && is_word_byte(s[start-1])) || (end < len(s) && is_word_byte(s[end]))
+}
+
+func is_word_byte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func has_non_ascii(s string) bool {
+	for idx := 0; idx < len(s); idx++ {
+		if s[idx] >= utf8.RuneSelf {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package filter
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// the Umm al-Qura calendar (the official Hijri calendar of Saudi Arabia) from 1365 to 1500 AH
+
+const (
+	hijri_first_year = 1365
+	hijri_last_year  = 1500
+)
+
+// 1365-01-01 AH
+var hijri_epoch = time.Date(1945, time.December, 5, 0, 0, 0, 0, time.UTC)
+
+// each year is a mask of its months, the bit (month - 1) is set when the month has 30 days (otherwise 29)
+var hijri_months = [hijri_last_year - hijri_first_year + 1]uint16{
+	0xd55, 0x555, 0x555, 0xd55, 0x6d5, 0x555, 0xea5, 0xd2a, 0xaaa, 0xcd5,
+	0x655, 0x572, 0xda9, 0x555, 0xaaa, 0x555, 0x52d, 0xa6d, 0x55a, 0x555,
+	0x74d, 0xd53, 0xd54, 0x556, 0xd55, 0x2d5, 0xd55, 0xd54, 0xd45, 0x655,
+	0x52d, 0xa5d, 0x55a, 0xad5, 0x6aa, 0xd4b, 0x52a, 0xa57, 0x4ae, 0x976,
+	0x56c, 0xb55, 0xaaa, 0xa55, 0x4ad, 0x95d, 0x2da, 0x5d9, 0xdb2, 0xba4,
+	0xb4a, 0xa55, 0x2b5, 0x575, 0xb6a, 0xbd2, 0xbc4, 0xb89, 0xa95, 0x52d,
+	0x5ad, 0xb6a, 0x6d4, 0xdc9, 0xd92, 0xaa6, 0x956, 0x2ae, 0x56d, 0x36a,
+	0xb55, 0xaaa, 0x94d, 0x49d, 0x95d, 0x2ba, 0x5b5, 0x5aa, 0xd55, 0xa9a,
+	0x92e, 0x26e, 0x55d, 0xada, 0x6d4, 0x6a5, 0x54b, 0xa97, 0x54e, 0xaae,
+	0x5ac, 0xba9, 0xd92, 0xb25, 0x64b, 0xcab, 0x55a, 0xb55, 0x6d2, 0xea5,
+	0xe4a, 0xa95, 0x52d, 0xaad, 0x36c, 0x759, 0x6d2, 0x695, 0x52d, 0xa5b,
+	0x4ba, 0x9ba, 0x3b4, 0xb69, 0xb52, 0xaa6, 0x4b6, 0x96d, 0x2ec, 0x6d9,
+	0xeb2, 0xd54, 0xd2a, 0xa56, 0x4ae, 0x96d, 0xd6a, 0xb54, 0xb29, 0xa93,
+	0x52b, 0xa57, 0x536, 0xab5, 0x6aa, 0xe93,
+}
+
+// YYYY-MM-DD or YYYY/MM/DD
+var hijri_date_regexp = regexp.MustCompile(`^(\d{4})[-/](\d{1,2})[-/](\d{1,2})$`)
+
+// hijri_year_starts[i] is the number of days from hijri_epoch to the first day of the year (hijri_first_year + i),
+// the last element is the day after the end of the table
+var hijri_year_starts = func() []int {
+	var starts = make([]int, 0, len(hijri_months)+1)
+	var days = 0
+
+	for _, mask := range hijri_months {
+		starts = append(starts, days)
+		for month := 0; month < 12; month++ {
+			days += hijri_month_len_of(mask, month)
+		}
+	}
+
+	return append(starts, days)
+}()
+
+func hijri_month_len_of(mask uint16, month int) int {
+	if mask&(1<<month) != 0 {
+		return 30
+	}
+	return 29
+}
+
+// hijri_month_len returns the number of days of the month (1-12) of the year or 0 if the year is out of the table
+func hijri_month_len(year int, month int) int {
+	if year < hijri_first_year || year > hijri_last_year || month < 1 || month > 12 {
+		return 0
+	}
+	return hijri_month_len_of(hijri_months[year-hijri_first_year], month-1)
+}
+
+// hijri_to_gregorian returns the Gregorian date (at midnight UTC) of a valid Umm al-Qura date
+func hijri_to_gregorian(year int, month int, day int) (time.Time, bool) {
+	if day < 1 || day > hijri_month_len(year, month) {
+		return time.Time{}, false
+	}
+
+	var idx = year - hijri_first_year
+	var days = hijri_year_starts[idx] + day - 1
+
+	for m := 0; m < month-1; m++ {
+		days += hijri_month_len_of(hijri_months[idx], m)
+	}
+
+	return hijri_epoch.AddDate(0, 0, days), true
+}
+
+// gregorian_to_hijri returns the Umm al-Qura date of the day of t
+func gregorian_to_hijri(t time.Time) (year int, month int, day int, ok bool) {
+	var days = int(utc_date(t).Sub(hijri_epoch).Hours() / 24)
+
+	if days < 0 || days >= hijri_year_starts[len(hijri_year_starts)-1] {
+		return 0, 0, 0, false
+	}
+
+	var idx = sort.Search(len(hijri_months), func(i int) bool {
+		return hijri_year_starts[i+1] > days
+	})
+
+	days -= hijri_year_starts[idx]
+
+	month = 0
+	for days >= hijri_month_len_of(hijri_months[idx], month) {
+		days -= hijri_month_len_of(hijri_months[idx], month)
+		month++
+	}
+
+	return hijri_first_year + idx, month + 1, days + 1, true
+}
+
+// format_hijri returns the date as (YYYY-MM-DD)
+func format_hijri(year int, month int, day int) string {
+	var s_month = strconv.Itoa(month)
+	if month < 10 {
+		s_month = "0" + s_month
+	}
+
+	var s_day = strconv.Itoa(day)
+	if day < 10 {
+		s_day = "0" + s_day
+	}
+
+	return strconv.Itoa(year) + "-" + s_month + "-" + s_day
+}
+
+// parse_hijri parses an Umm al-Qura date (YYYY-MM-DD) and returns its Gregorian date at midnight UTC
+func parse_hijri(input string) (time.Time, bool) {
+	var parts = hijri_date_regexp.FindStringSubmatch(input)
+
+	if parts == nil {
+		return time.Time{}, false
+	}
+
+	var year, _ = strconv.Atoi(parts[1])
+	var month, _ = strconv.Atoi(parts[2])
+	var day, _ = strconv.Atoi(parts[3])
+
+	return hijri_to_gregorian(year, month, day)
+}
@@ -0,0 +1,41 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// decode_json_value decodes data keeping the integral numbers as int (not float64)
+func decode_json_value(data []byte) (any, error) {
+	var dec = json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return convert_json_numbers(v), nil
+}
+
+// convert_json_numbers replaces each json.Number of v (recursively) with an int or a float64
+func convert_json_numbers(v any) any {
+	switch t := v.(type) {
+	case json.Number:
+		if num, err := strconv.Atoi(t.String()); err == nil {
+			return num
+		}
+		var f_num, _ = t.Float64()
+		return f_num
+	case []any:
+		for idx, el := range t {
+			t[idx] = convert_json_numbers(el)
+		}
+	case map[string]any:
+		for key, el := range t {
+			t[key] = convert_json_numbers(el)
+		}
+	}
+	return v
+}
@@ -1,5 +1,7 @@
 package filter
 
+import "net/url"
+
 func get_first_el_if_exists(m map[string][]string, key string) (string, bool) {
 	var els []string
 	var ok bool
@@ -29,3 +31,13 @@ func get_val_if_exists(m map[string][]string, key string) ([]string, bool) {
 	return els, true
 
 }
+
+// FirstValue returns the first value of key in v
+func FirstValue(v url.Values, key string) (string, bool) {
+	return get_first_el_if_exists(v, key)
+}
+
+// Values returns all the values of key in v (false if there is not any)
+func Values(v url.Values, key string) ([]string, bool) {
+	return get_val_if_exists(v, key)
+}
@@ -0,0 +1,8 @@
+package filter
+
+// Catalog returns the message templates of the errors, a template may contain
+// named parameters like {min} which are replaced when the error is created
+type Catalog interface {
+	// Message returns the template of id in lang (exactly, without any fallback)
+	Message(lang string, id string) (string, bool)
+}
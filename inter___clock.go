@@ -0,0 +1,9 @@
+package filter
+
+import "time"
+
+// Clock returns the current time, the relative dates (today, -7d, ...) and
+// the presets are evaluated against it, so it can be frozen in the tests
+type Clock interface {
+	Now() time.Time
+}
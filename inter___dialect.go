@@ -0,0 +1,32 @@
+package filter
+
+import "time"
+
+// Dialect renders the parts of the query that differ between database engines
+type Dialect interface {
+	// Placeholder returns the placeholder of the argument at idx (starts from 1)
+	Placeholder(idx int) string
+	// QuoteIdent quotes a single identifier (without dots)
+	QuoteIdent(ident string) string
+	// QuoteString returns v as an escaped string literal
+	QuoteString(v string) string
+	// Bool returns the boolean literal of v
+	Bool(v bool) string
+	// Timestamp returns the timestamp literal of t (t is already in the configured zone)
+	Timestamp(t time.Time) string
+	// EscapeLike escapes the wildcards (and the escape character) of v so it is matched literally by Like and ILike
+	EscapeLike(v string) string
+	// Like returns a condition that matches col against the (already bound) pattern
+	Like(col string, pattern string) string
+	// ILike is the case-insensitive version of Like
+	ILike(col string, pattern string) string
+	// LimitOffset binds limit and offset using bind, ordered reports
+	// whether the query already has an ORDER BY clause
+	LimitOffset(bind func(v any) string, limit int, offset int, ordered bool) string
+	// Limit is the same as LimitOffset without offset (used by cursor pagination)
+	Limit(bind func(v any) string, limit int, ordered bool) string
+	// SortKey returns the ORDER BY entry of expr
+	SortKey(expr string, desc bool, nulls NullsOrder) string
+	// RowValues reports whether row values can be compared: (a,b)>(1,2)
+	RowValues() bool
+}
@@ -2,6 +2,9 @@ package filter
 
 import "net/url"
 
+// Filter validates its own keys of v and returns the condition (or an empty string if it does not have any),
+// the values should be added to the condition only through ctx (Bind, BindList) and
+// the columns through ctx.Ident, so the condition works with every dialect and with the (WithArgs) methods
 type Filter interface {
-	validate_and_construct(v url.Values, language string) (string, error)
+	ValidateAndConstruct(v url.Values, language string, ctx *SqlCtx) (string, error)
 }
@@ -1,9 +1,8 @@
 package filter
 
 import (
+	"bytes"
 	"encoding/json"
-	"strconv"
-	"strings"
 )
 
 var OmitVal = &[]int8{}
@@ -12,55 +11,119 @@ type FilterErr struct {
 	Key     string
 	Value   any
 	Path    []any
+	Code    string         // stable id of the error (the id of its message in the catalog): number.too_small
+	Params  map[string]any // parameters of the message: {"min": 18}
 	Message string
+
+	ar_digits bool // FilterConfigs.ArabicDigits is enabled (the Arabic messages are written in Eastern Arabic digits)
 }
 
 func (f *FilterErr) Error() string {
 	return f.Message
 }
 
+// filter_err_json is the JSON form of FilterErr (the order of the fields is the order of the document)
+type filter_err_json struct {
+	Key     string          `json:"key,omitempty"`
+	Value   json.RawMessage `json:"value,omitempty"` // missing when the value is OmitVal
+	Path    []any           `json:"path"`
+	Code    string          `json:"code,omitempty"`
+	Params  map[string]any  `json:"params,omitempty"`
+	Message string          `json:"message,omitempty"`
+}
+
 func (f *FilterErr) MarshalJSON() ([]byte, error) {
-	var js = ""
-	if f.Key != "" {
-		js += `,"key":"` + strings.ReplaceAll(f.Key, `"`, `\"`) + `"`
+	var js = filter_err_json{
+		Key:     f.Key,
+		Path:    f.Path,
+		Code:    f.Code,
+		Params:  f.Params,
+		Message: f.Message,
+	}
+
+	if js.Path == nil {
+		js.Path = []any{}
 	}
 
 	if f.Value != OmitVal {
-		js += `,"value":`
 		var err error
-		var jsVal []byte
-
-		if jsVal, err = json.Marshal(f.Value); err != nil {
+		if js.Value, err = json.Marshal(f.Value); err != nil {
 			return nil, err
 		}
+	}
+
+	return json.Marshal(js)
+}
 
-		js += string(jsVal)
+// UnmarshalJSON decodes the JSON form of the error, a missing value is decoded as OmitVal
+// and the integral numbers (of the value, the path and the params) are decoded as int
+func (f *FilterErr) UnmarshalJSON(data []byte) error {
+	var js filter_err_json
+
+	var dec = json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if err := dec.Decode(&js); err != nil {
+		return err
 	}
 
-	var els = ""
-	for _, el := range f.Path {
-		switch t := el.(type) {
-		case int:
-			els += "," + strconv.Itoa(t)
-		case string:
-			els += `,"` + strings.ReplaceAll(t, `"`, `\"`) + `"`
-		}
+	var decoded = FilterErr{
+		Key:     js.Key,
+		Value:   OmitVal,
+		Code:    js.Code,
+		Message: js.Message,
 	}
 
-	if len(els) > 0 {
-		els = els[1:]
+	if js.Value != nil {
+		var err error
+		if decoded.Value, err = decode_json_value(js.Value); err != nil {
+			return err
+		}
 	}
 
-	js += `,"path":[` + els + `]`
+	for _, el := range js.Path {
+		decoded.Path = append(decoded.Path, convert_json_numbers(el))
+	}
 
-	if f.Message != "" {
-		js += `,"message":"` + strings.ReplaceAll(f.Message, `"`, `\"`) + `"`
+	if js.Params != nil {
+		decoded.Params = convert_json_numbers(js.Params).(map[string]any)
 	}
 
-	if len(js) == 0 {
-		return []byte("{}"), nil
+	*f = decoded
+
+	return nil
+}
+
+func NewFilterErr(key string, value any, message string, path ...any) *FilterErr {
+	return &FilterErr{
+		Key:     key,
+		Value:   value,
+		Path:    path,
+		Message: message,
 	}
+}
 
-	return []byte("{" + js[1:] + "}"), nil
+// NewCodedFilterErr returns the error of code with its message translated to lang from the catalog
+// (the messages of the custom codes are added with RegisterMessages), params fill the placeholders of the message:
+// NewCodedFilterErr("age", val, "range.invalid", map[string]any{"min": 18}, lang, "between")
+func NewCodedFilterErr(key string, value any, code string, params map[string]any, lang string, path ...any) *FilterErr {
+	return new_err(key, value, message{code: code, params: params}, lang, path...)
+}
 
+// message is the code of an error with the parameters of its message
+type message struct {
+	code   string
+	params map[string]any
+}
+
+// new_err returns the error of msg with its message translated to lang
+func new_err(key string, value any, msg message, lang string, path ...any) *FilterErr {
+	return &FilterErr{
+		Key:     key,
+		Value:   value,
+		Path:    path,
+		Code:    msg.code,
+		Params:  msg.params,
+		Message: translate(lang, msg.code, msg.params),
+	}
 }
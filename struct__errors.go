@@ -1,5 +1,7 @@
 package filter
 
+import "encoding/json"
+
 type FilterErrs []error
 
 func (e *FilterErrs) Error() string {
@@ -15,3 +17,50 @@ func (e *FilterErrs) Error() string {
 
 	return "[" + errs + "\n]"
 }
+
+// filter_errs_json is the envelope of FilterErrs: {"errors":[...]}
+type filter_errs_json struct {
+	Errors []*FilterErr `json:"errors"`
+}
+
+// MarshalJSON returns the errors inside an envelope: {"errors":[{"key":"age",...}]},
+// the errors that are not *FilterErr have only a message
+func (e FilterErrs) MarshalJSON() ([]byte, error) {
+	var js = filter_errs_json{
+		Errors: make([]*FilterErr, 0, len(e)),
+	}
+
+	for _, err := range e {
+		var f_err, ok = err.(*FilterErr)
+		if !ok {
+			f_err = &FilterErr{
+				Value:   OmitVal,
+				Message: err.Error(),
+			}
+		}
+		js.Errors = append(js.Errors, f_err)
+	}
+
+	return json.Marshal(js)
+}
+
+// UnmarshalJSON decodes the envelope of MarshalJSON, each error is decoded as *FilterErr
+func (e *FilterErrs) UnmarshalJSON(data []byte) error {
+	var js filter_errs_json
+
+	if err := json.Unmarshal(data, &js); err != nil {
+		return err
+	}
+
+	var errs = make(FilterErrs, 0, len(js.Errors))
+	for _, f_err := range js.Errors {
+		if f_err == nil {
+			continue
+		}
+		errs = append(errs, f_err)
+	}
+
+	*e = errs
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package filter
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type PlaceholderFormat int
+
+const (
+	QuestionPlaceholder PlaceholderFormat = iota + 1 // ?, ?, ...
+	DollarPlaceholder                                // $1, $2, ...
+)
+
+// raw_literal is inlined as sql (which should be already validated)
+// or bound as arg when the query is parameterized
+type raw_literal struct {
+	sql string
+	arg any
+}
+
+// SqlCtx is shared by all the filters of a single query,
+// it either inlines the values as escaped literals or
+// replaces them with placeholders and collects them in order
+type SqlCtx struct {
+	dialect      Dialect
+	quote_idents bool
+	parameterize bool
+	placeholder  PlaceholderFormat
+	args         []any
+	now          time.Time
+}
+
+// Bind returns a placeholder for v (or v as an escaped literal when the query is not parameterized),
+// v is usually a string, a number (of any integer or float kind), a bool or a time.Time,
+// nil is inlined as NULL and the other values are inlined as strings (driver.Valuer through its value)
+func (c *SqlCtx) Bind(v any) string {
+	if c.parameterize {
+		if lit, ok := v.(raw_literal); ok {
+			v = lit.arg
+		}
+
+		c.args = append(c.args, v)
+
+		switch c.placeholder {
+		case QuestionPlaceholder:
+			return "?"
+		case DollarPlaceholder:
+			return "$" + strconv.Itoa(len(c.args))
+		}
+		return c.dialect.Placeholder(len(c.args))
+	}
+
+	return c.literal(v)
+}
+
+func (c *SqlCtx) literal(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return c.dialect.QuoteString(t)
+	case []byte:
+		return c.dialect.QuoteString(string(t))
+	case int:
+		return strconv.Itoa(t)
+	case float64:
+		return format_float(t, 64)
+	case bool:
+		return c.dialect.Bool(t)
+	case time.Time:
+		return c.dialect.Timestamp(t)
+	case raw_literal:
+		return t.sql
+	case driver.Valuer:
+		if val, err := t.Value(); err == nil && val != v {
+			return c.literal(val)
+		}
+	}
+
+	// the named types (type Status int) and the other sizes of the numbers
+	var r = reflect.ValueOf(v)
+
+	switch r.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(r.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(r.Uint(), 10)
+	case reflect.Float32:
+		return format_float(r.Float(), 32)
+	case reflect.Float64:
+		return format_float(r.Float(), 64)
+	case reflect.String:
+		return c.dialect.QuoteString(r.String())
+	case reflect.Bool:
+		return c.dialect.Bool(r.Bool())
+	case reflect.Pointer:
+		if r.IsNil() {
+			return "NULL"
+		}
+		return c.literal(r.Elem().Interface())
+	}
+
+	return c.dialect.QuoteString(fmt.Sprint(v))
+}
+
+// format_float quotes NaN and the infinities (they are not numeric literals)
+func format_float(f float64, bits int) string {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return to_escaped_string(strconv.FormatFloat(f, 'f', -1, bits))
+	}
+	return strconv.FormatFloat(f, 'f', -1, bits)
+}
+
+// BindList binds each value and joins them with a comma (for IN lists)
+func (c *SqlCtx) BindList(vals []any) string {
+	var bound = make([]string, 0, len(vals))
+	for _, v := range vals {
+		bound = append(bound, c.Bind(v))
+	}
+	return strings.Join(bound, ",")
+}
+
+// Ident quotes each part of a (possibly qualified) column name when quoting is enabled,
+// otherwise (or if col is an SQL expression like lower(name)) it returns col as is
+func (c *SqlCtx) Ident(col string) string {
+	if !c.quote_idents || !is_ident(col) {
+		return col
+	}
+
+	var parts = strings.Split(col, ".")
+	for idx, part := range parts {
+		parts[idx] = c.dialect.QuoteIdent(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+func (c *SqlCtx) Dialect() Dialect {
+	return c.dialect
+}
+
+// Now returns the time of the clock of the filters, it is read once per query
+// so all the filters of the query see the same instant
+func (c *SqlCtx) Now() time.Time {
+	if c.now.IsZero() {
+		return time.Now()
+	}
+	return c.now
+}
+
+// is_ident reports whether col is a (possibly qualified) plain identifier: users.first_name
+func is_ident(col string) bool {
+	if col == "" {
+		return false
+	}
+
+	for _, part := range strings.Split(col, ".") {
+		if part == "" {
+			return false
+		}
+
+		for idx, r := range part {
+			if r == '_' || unicode.IsLetter(r) || (idx > 0 && unicode.IsDigit(r)) {
+				continue
+			}
+			return false
+		}
+	}
+
+	return true
+}
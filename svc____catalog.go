@@ -0,0 +1,154 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// the last language of every fallback chain
+const fallback_lang = "en"
+
+// MessageCatalog is a Catalog of in-memory bundles, it is safe for concurrent use
+type MessageCatalog struct {
+	mu      sync.RWMutex
+	bundles map[string]map[string]string
+}
+
+// NewMessageCatalog returns a catalog with the built-in (ar) and (en) bundles
+func NewMessageCatalog() *MessageCatalog {
+	var c = &MessageCatalog{
+		bundles: map[string]map[string]string{},
+	}
+
+	for lang, msgs := range builtin_messages {
+		c.Register(lang, msgs)
+	}
+
+	return c
+}
+
+// Register adds the messages of lang (a new language or overrides of some messages of an existing one)
+func (c *MessageCatalog) Register(lang string, msgs map[string]string) {
+	lang = normalize_lang(lang)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var bundle, ok = c.bundles[lang]
+	if !ok {
+		bundle = make(map[string]string, len(msgs))
+		c.bundles[lang] = bundle
+	}
+
+	for id, msg := range msgs {
+		bundle[id] = msg
+	}
+}
+
+func (c *MessageCatalog) Message(lang string, id string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var msg, ok = c.bundles[normalize_lang(lang)][id]
+	return msg, ok
+}
+
+// DefaultCatalog is the catalog of the errors until it is replaced by SetCatalog
+var DefaultCatalog = NewMessageCatalog()
+
+var catalog Catalog = DefaultCatalog
+
+// SetCatalog replaces the catalog of the errors (it should be called before using the filters)
+func SetCatalog(c Catalog) {
+	catalog = c
+}
+
+// RegisterMessages adds the messages of lang to DefaultCatalog: RegisterMessages("fr", map[string]string{"date.invalid": "La date est invalide"})
+func RegisterMessages(lang string, msgs map[string]string) {
+	DefaultCatalog.Register(lang, msgs)
+}
+
+// translate returns the message of id in lang with its parameters replaced,
+// the languages are tried from the most specific one: ar-SA => ar => en
+func translate(lang string, id string, params map[string]any) string {
+	var tmpl, ok = "", false
+
+	for _, l := range fallback_langs(lang) {
+		if tmpl, ok = catalog.Message(l, id); ok {
+			break
+		}
+	}
+
+	if !ok {
+		return id
+	}
+
+	if len(params) == 0 {
+		return tmpl
+	}
+
+	var pairs = make([]string, 0, len(params)*2)
+	for name, val := range params {
+		pairs = append(pairs, "{"+name+"}", format_param(val))
+	}
+
+	return strings.NewReplacer(pairs...).Replace(tmpl)
+}
+
+// format_param returns the text of a parameter, the lists are separated by commas: [1, 2] => "1, 2"
+func format_param(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case int:
+		return strconv.Itoa(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case []string:
+		return strings.Join(t, ", ")
+	case []int:
+		return strings.Join(sl_of_int_to_sl_of_str(t), ", ")
+	case fmt.Stringer:
+		return t.String()
+	}
+	return fmt.Sprint(v)
+}
+
+// fallback_langs returns the chain of lang: ar-SA => [ar-sa, ar, en]
+func fallback_langs(lang string) []string {
+	lang = normalize_lang(lang)
+
+	var langs = []string{}
+
+	for lang != "" {
+		langs = append(langs, lang)
+
+		var idx = strings.LastIndexByte(lang, '-')
+		if idx < 0 {
+			break
+		}
+		lang = lang[:idx]
+	}
+
+	if len(langs) == 0 || langs[len(langs)-1] != fallback_lang {
+		langs = append(langs, fallback_lang)
+	}
+
+	return langs
+}
+
+// primary_lang returns the language without its region: ar-SA => ar
+func primary_lang(lang string) string {
+	lang = normalize_lang(lang)
+
+	if idx := strings.IndexByte(lang, '-'); idx > -1 {
+		return lang[:idx]
+	}
+	return lang
+}
+
+func normalize_lang(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
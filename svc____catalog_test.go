@@ -0,0 +1,126 @@
+package filter_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/MaSTeR2W/filter"
+)
+
+func TestCatalog(t *testing.T) {
+	var sql = "SELECT * FROM users"
+
+	var fs = filter.NewFilters(
+		filter.FilterConfigs{
+			SqlSelect: sql,
+			Paginate:  true,
+			LimitMax:  50,
+		},
+		filter.NewIntFilter(filter.IntFilterOpts{
+			Key:       "age",
+			EnableMin: true,
+			Min:       18,
+		}),
+	)
+
+	var first_msg = func(t *testing.T, v url.Values, lang string) string {
+		var _, err = fs.ValidateAndConstruct(v, lang)
+
+		if err == nil {
+			t.Error("should throw error")
+			return ""
+		}
+
+		return (*err.(*filter.FilterErrs))[0].(*filter.FilterErr).Message
+	}
+
+	var test_fallback = func(t *testing.T) {
+		var v = url.Values{
+			"age[eq]": []string{"12"},
+		}
+
+		var cases = map[string]string{
+			"ar":    "يجب أن يكون العدد أكبر من أو يساوي 18",
+			"ar-SA": "يجب أن يكون العدد أكبر من أو يساوي 18",
+			"ar_sa": "يجب أن يكون العدد أكبر من أو يساوي 18",
+			"en-US": "The number should be greater than or equal to 18",
+			"tr":    "The number should be greater than or equal to 18",
+			"":      "The number should be greater than or equal to 18",
+		}
+
+		for lang, msg := range cases {
+			if got := first_msg(t, v, lang); got != msg {
+				t.Error("invalid message:", lang, got)
+			}
+		}
+	}
+
+	t.Run("test_fallback", test_fallback)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_register = func(t *testing.T) {
+		filter.RegisterMessages("fr", map[string]string{
+			"number.too_small":  "Le nombre doit être supérieur ou égal à {min}",
+			"limit.exceeds_max": "La limite ne doit pas dépasser {max}",
+		})
+
+		var v = url.Values{
+			"age[eq]": []string{"12"},
+		}
+
+		if got := first_msg(t, v, "fr-CA"); got != "Le nombre doit être supérieur ou égal à 18" {
+			t.Error("invalid message:", got)
+		}
+
+		// the missing messages fall back to English
+		v = url.Values{
+			"age[eq]": []string{"abc"},
+		}
+
+		if got := first_msg(t, v, "fr"); got != "invalid number" {
+			t.Error("invalid message:", got)
+		}
+	}
+
+	t.Run("test_register", test_register)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_override = func(t *testing.T) {
+		var c = filter.NewMessageCatalog()
+
+		c.Register("en", map[string]string{
+			"limit.exceeds_max": "At most {max} rows per page",
+		})
+
+		filter.SetCatalog(c)
+		defer filter.SetCatalog(filter.DefaultCatalog)
+
+		var v = url.Values{
+			"$page":  []string{"1"},
+			"$limit": []string{"100"},
+		}
+
+		if got := first_msg(t, v, "en"); got != "At most 50 rows per page" {
+			t.Error("invalid message:", got)
+		}
+
+		// the other messages are not affected
+		if got := first_msg(t, v, "ar"); got != "يجب ألا يتجاوز الحد 50" {
+			t.Error("invalid message:", got)
+		}
+	}
+
+	t.Run("test_override", test_override)
+}
@@ -0,0 +1,13 @@
+package filter
+
+import "time"
+
+// ClockFunc adapts a function to Clock: ClockFunc(func() time.Time { return fixed })
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock is the default clock (time.Now)
+var SystemClock Clock = ClockFunc(time.Now)
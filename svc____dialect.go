@@ -0,0 +1,233 @@
+package filter
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	Postgres  Dialect = postgres_dialect{}
+	MySQL     Dialect = mysql_dialect{}
+	SQLite    Dialect = sqlite_dialect{}
+	SQLServer Dialect = sqlserver_dialect{}
+)
+
+// generic_dialect is used when no dialect is configured,
+// it renders the same SQL the package used to render before dialects
+type generic_dialect struct{}
+
+func (generic_dialect) Placeholder(idx int) string {
+	return "?"
+}
+
+func (generic_dialect) QuoteIdent(ident string) string {
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
+
+func (generic_dialect) QuoteString(v string) string {
+	return to_escaped_string(v)
+}
+
+func (generic_dialect) Bool(v bool) string {
+	if v {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func (generic_dialect) Timestamp(t time.Time) string {
+	return "'" + t.Format("2006-01-02 15:04:05.999999-07:00") + "'"
+}
+
+// backslash is the default escape character of LIKE in PostgreSQL and MySQL
+func (generic_dialect) EscapeLike(v string) string {
+	return like_escaper.Replace(v)
+}
+
+func (generic_dialect) Like(col string, pattern string) string {
+	return col + " LIKE " + pattern
+}
+
+func (generic_dialect) ILike(col string, pattern string) string {
+	return "LOWER(" + col + ") LIKE LOWER(" + pattern + ")"
+}
+
+func (generic_dialect) LimitOffset(bind func(v any) string, limit int, offset int, ordered bool) string {
+	return "LIMIT " + bind(limit) + " OFFSET " + bind(offset)
+}
+
+func (generic_dialect) Limit(bind func(v any) string, limit int, ordered bool) string {
+	return "LIMIT " + bind(limit)
+}
+
+func (generic_dialect) RowValues() bool {
+	return true
+}
+
+func (generic_dialect) SortKey(expr string, desc bool, nulls NullsOrder) string {
+	var key = expr + " " + direction(desc)
+
+	switch nulls {
+	case NullsFirst:
+		key += " NULLS FIRST"
+	case NullsLast:
+		key += " NULLS LAST"
+	}
+
+	return key
+}
+
+type postgres_dialect struct {
+	generic_dialect
+}
+
+func (postgres_dialect) Placeholder(idx int) string {
+	return "$" + strconv.Itoa(idx)
+}
+
+func (postgres_dialect) ILike(col string, pattern string) string {
+	return col + " ILIKE " + pattern
+}
+
+type mysql_dialect struct {
+	generic_dialect
+}
+
+func (mysql_dialect) QuoteIdent(ident string) string {
+	return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
+}
+
+// backslash is an escape character inside MySQL string literals
+// (unless NO_BACKSLASH_ESCAPES is enabled), so it should be escaped too
+func (mysql_dialect) QuoteString(v string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(v) + "'"
+}
+
+func (mysql_dialect) SortKey(expr string, desc bool, nulls NullsOrder) string {
+	return emulated_nulls_sort_key(expr, desc, nulls)
+}
+
+// DATETIME does not accept offsets before MySQL 8.0.19
+func (mysql_dialect) Timestamp(t time.Time) string {
+	return "'" + t.Format("2006-01-02 15:04:05.999999") + "'"
+}
+
+type sqlite_dialect struct {
+	generic_dialect
+}
+
+// SQLite does not have a boolean type (TRUE and FALSE are accepted since 3.23 only)
+func (sqlite_dialect) Bool(v bool) string {
+	return bit(v)
+}
+
+// the timestamps are stored as text, so they are compared as text
+func (sqlite_dialect) Timestamp(t time.Time) string {
+	return "'" + t.Format("2006-01-02 15:04:05.999") + "'"
+}
+
+// LIKE does not have a default escape character in SQLite
+func (sqlite_dialect) Like(col string, pattern string) string {
+	return col + " LIKE " + pattern + ` ESCAPE '\'`
+}
+
+func (sqlite_dialect) ILike(col string, pattern string) string {
+	return "LOWER(" + col + ") LIKE LOWER(" + pattern + `) ESCAPE '\'`
+}
+
+type sqlserver_dialect struct {
+	generic_dialect
+}
+
+func (sqlserver_dialect) Placeholder(idx int) string {
+	return "@p" + strconv.Itoa(idx)
+}
+
+func (sqlserver_dialect) QuoteIdent(ident string) string {
+	return "[" + strings.ReplaceAll(ident, "]", "]]") + "]"
+}
+
+// bit columns are compared with 1 and 0
+func (sqlserver_dialect) Bool(v bool) string {
+	return bit(v)
+}
+
+// ISO 8601 (with T) does not depend on DATEFORMAT and it can be converted to datetime2
+func (sqlserver_dialect) Timestamp(t time.Time) string {
+	return "'" + t.Format("2006-01-02T15:04:05.9999999") + "'"
+}
+
+// N prefix keeps the non latin characters when comparing with nvarchar columns
+func (sqlserver_dialect) QuoteString(v string) string {
+	return "N" + to_escaped_string(v)
+}
+
+// [ starts a character range in the patterns of SQL Server
+func (sqlserver_dialect) EscapeLike(v string) string {
+	return sqlserver_like_escaper.Replace(v)
+}
+
+// LIKE does not have a default escape character in SQL Server
+func (sqlserver_dialect) Like(col string, pattern string) string {
+	return col + " LIKE " + pattern + ` ESCAPE '\'`
+}
+
+func (sqlserver_dialect) ILike(col string, pattern string) string {
+	return "LOWER(" + col + ") LIKE LOWER(" + pattern + `) ESCAPE '\'`
+}
+
+// OFFSET FETCH is not allowed without ORDER BY
+func (sqlserver_dialect) LimitOffset(bind func(v any) string, limit int, offset int, ordered bool) string {
+	var order_by = ""
+	if !ordered {
+		order_by = "ORDER BY (SELECT NULL) "
+	}
+	return order_by + "OFFSET " + bind(offset) + " ROWS FETCH NEXT " + bind(limit) + " ROWS ONLY"
+}
+
+func (sqlserver_dialect) Limit(bind func(v any) string, limit int, ordered bool) string {
+	var order_by = ""
+	if !ordered {
+		order_by = "ORDER BY (SELECT NULL) "
+	}
+	return order_by + "OFFSET 0 ROWS FETCH NEXT " + bind(limit) + " ROWS ONLY"
+}
+
+func (sqlserver_dialect) RowValues() bool {
+	return false
+}
+
+func (sqlserver_dialect) SortKey(expr string, desc bool, nulls NullsOrder) string {
+	return emulated_nulls_sort_key(expr, desc, nulls)
+}
+
+var (
+	like_escaper           = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	sqlserver_like_escaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`, "[", `\[`)
+)
+
+func bit(v bool) string {
+	if v {
+		return "1"
+	}
+	return "0"
+}
+
+func direction(desc bool) string {
+	if desc {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// emulated_nulls_sort_key is used by the engines that do not support (NULLS FIRST, NULLS LAST)
+func emulated_nulls_sort_key(expr string, desc bool, nulls NullsOrder) string {
+	switch nulls {
+	case NullsFirst:
+		return "CASE WHEN " + expr + " IS NULL THEN 0 ELSE 1 END, " + expr + " " + direction(desc)
+	case NullsLast:
+		return "CASE WHEN " + expr + " IS NULL THEN 1 ELSE 0 END, " + expr + " " + direction(desc)
+	}
+	return expr + " " + direction(desc)
+}
@@ -0,0 +1,173 @@
+package filter_test
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/MaSTeR2W/filter"
+)
+
+func TestDialect(t *testing.T) {
+	var new_filters = func(dialect filter.Dialect, order_by []string) interface {
+		ValidateAndConstruct(url.Values, string) (string, error)
+		ValidateAndConstructWithArgs(url.Values, string) (string, []any, error)
+	} {
+		return filter.NewFilters(filter.FilterConfigs{
+			SqlSelect:   "SELECT * FROM users",
+			Paginate:    true,
+			OrderBy:     order_by,
+			Dialect:     dialect,
+			QuoteIdents: true,
+		}, filter.NewStrFilter(filter.StrFilterOpts{
+			Key:      "name",
+			ColAlias: "u.name",
+		}))
+	}
+
+	var v = url.Values{
+		"name[sw]": []string{`it's\`},
+		"$limit":   []string{"10"},
+		"$page":    []string{"3"},
+	}
+
+	var test_postgres = func(t *testing.T) {
+		var fs = new_filters(filter.Postgres, nil)
+
+		var query, err = fs.ValidateAndConstruct(v, "en")
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != `SELECT * FROM users WHERE "u"."name" LIKE 'it''s\\%' LIMIT 10 OFFSET 20` {
+			t.Error("invalid query:", query)
+		}
+
+		var args []any
+		query, args, err = fs.ValidateAndConstructWithArgs(v, "en")
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != `SELECT * FROM users WHERE "u"."name" LIKE $1 LIMIT $2 OFFSET $3` {
+			t.Error("invalid query:", query)
+		}
+
+		if !reflect.DeepEqual(args, []any{`it's\\%`, 10, 20}) {
+			t.Error("invalid args:", args)
+		}
+	}
+
+	t.Run("test_postgres", test_postgres)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_mysql = func(t *testing.T) {
+		var fs = new_filters(filter.MySQL, nil)
+
+		var query, err = fs.ValidateAndConstruct(v, "en")
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != "SELECT * FROM users WHERE `u`.`name` LIKE 'it''s\\\\\\\\%' LIMIT 10 OFFSET 20" {
+			t.Error("invalid query:", query)
+		}
+
+		query, _, err = fs.ValidateAndConstructWithArgs(v, "en")
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != "SELECT * FROM users WHERE `u`.`name` LIKE ? LIMIT ? OFFSET ?" {
+			t.Error("invalid query:", query)
+		}
+	}
+
+	t.Run("test_mysql", test_mysql)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_sqlite = func(t *testing.T) {
+		var fs = new_filters(filter.SQLite, nil)
+
+		var query, err = fs.ValidateAndConstruct(v, "en")
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != `SELECT * FROM users WHERE "u"."name" LIKE 'it''s\\%' ESCAPE '\' LIMIT 10 OFFSET 20` {
+			t.Error("invalid query:", query)
+		}
+	}
+
+	t.Run("test_sqlite", test_sqlite)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_sqlserver = func(t *testing.T) {
+		var fs = new_filters(filter.SQLServer, nil)
+
+		var query, err = fs.ValidateAndConstruct(v, "en")
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != `SELECT * FROM users WHERE [u].[name] LIKE N'it''s\\%' ESCAPE '\' ORDER BY (SELECT NULL) OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY` {
+			t.Error("invalid query:", query)
+		}
+
+		fs = new_filters(filter.SQLServer, []string{"name"})
+
+		var w = url.Values{
+			"$order_by": []string{"name"},
+			"$limit":    []string{"10"},
+			"$page":     []string{"3"},
+		}
+
+		var args []any
+		query, args, err = fs.ValidateAndConstructWithArgs(w, "en")
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != `SELECT * FROM users ORDER BY [name] ASC OFFSET @p1 ROWS FETCH NEXT @p2 ROWS ONLY` {
+			t.Error("invalid query:", query)
+		}
+
+		if !reflect.DeepEqual(args, []any{20, 10}) {
+			t.Error("invalid args:", args)
+		}
+	}
+
+	t.Run("test_sqlserver", test_sqlserver)
+}
@@ -1,35 +1,79 @@
 package filter
 
-import "net/url"
+import (
+	"net/url"
+	"strings"
+)
 
 type filters struct {
-	sql_select string
-	sql_count  string
-	filters    []Filter
-	len        int
-	paginate   bool
-	paginator  *paginator
-	ordering   bool
-	orderer    orderer
+	sql_select  string
+	sql_count   string
+	filters     []Filter
+	len         int
+	paginate    bool
+	paginator   *paginator
+	ordering    bool
+	orderer     orderer
+	placeholder PlaceholderFormat
+	dialect     Dialect
+	quote       bool
+	grouping    bool
+	group_opts  *group_opts
+	cursor      bool
+	tie_breaker string
+	clock       Clock
+	ar_digits   bool
 }
 
 type FilterConfigs struct {
-	SqlSelect string
-	SqlCount  string
-	Paginate  bool
-	LimitMin  int // minimum allowed value for limit
-	LimitMax  int // maximum allowed value for limit
-	OrderBy   []string
+	SqlSelect      string
+	SqlCount       string
+	Paginate       bool
+	LimitMin       int               // minimum allowed value for limit
+	LimitMax       int               // maximum allowed value for limit
+	OrderBy        []string          // sortable columns, the same as (OrderByOpts) with only Key
+	OrderByOpts    []OrderByOpts     // sortable keys with their SQL expressions
+	OrderByMax     int               // maximum number of sort keys (default: the number of sortable keys)
+	OrderByDefault []string          // sort keys used when ($order_by) is missing, e.g. "-createdAt"
+	Placeholder    PlaceholderFormat // used by the (WithArgs) methods, defaults to the placeholder of the dialect
+	Dialect        Dialect           // Postgres, MySQL, SQLite, SQLServer or a custom one
+	QuoteIdents    bool              // quote the column aliases using the dialect
+	Groups         bool              // accept ($or, $and, $not) groups of conditions
+	GroupDepth     int               // maximum nesting depth of groups (default 3)
+	GroupLeaves    int               // maximum number of conditions inside groups (default 20)
+	Cursor         bool              // paginate by ($cursor, $limit) instead of ($page, $limit)
+	TieBreaker     string            // unique column always appended to the sort (required by Cursor)
+	Clock          Clock             // the current time of the date filters (default: SystemClock)
+	ArabicDigits   bool              // write the numbers of the Arabic error messages in Eastern Arabic digits (٠-٩)
 }
 
 func NewFilters(cfg FilterConfigs, fs ...Filter) *filters {
 
 	var f = filters{
-		sql_select: cfg.SqlSelect,
-		sql_count:  cfg.SqlCount,
-		filters:    fs,
-		paginate:   cfg.Paginate,
-		len:        len(fs),
+		sql_select:  cfg.SqlSelect,
+		sql_count:   cfg.SqlCount,
+		filters:     fs,
+		paginate:    cfg.Paginate,
+		len:         len(fs),
+		placeholder: cfg.Placeholder,
+		dialect:     cfg.Dialect,
+		quote:       cfg.QuoteIdents,
+		cursor:      cfg.Paginate && cfg.Cursor,
+		tie_breaker: cfg.TieBreaker,
+		clock:       cfg.Clock,
+		ar_digits:   cfg.ArabicDigits,
+	}
+
+	if f.cursor && f.tie_breaker == "" {
+		panic("filter: cursor pagination requires a TieBreaker")
+	}
+
+	if f.dialect == nil {
+		f.dialect = generic_dialect{}
+	}
+
+	if f.clock == nil {
+		f.clock = SystemClock
 	}
 
 	if cfg.Paginate {
@@ -47,126 +91,198 @@ func NewFilters(cfg FilterConfigs, fs ...Filter) *filters {
 		f.paginator = new_pagintor(pgOpts)
 	}
 
-	if cfg.OrderBy != nil {
+	if cfg.Groups {
+		f.grouping = true
+		f.group_opts = new_group_opts(cfg.GroupDepth, cfg.GroupLeaves)
+	}
+
+	if cfg.OrderBy != nil || cfg.OrderByOpts != nil || cfg.OrderByDefault != nil {
+		var cols = make([]OrderByOpts, 0, len(cfg.OrderBy)+len(cfg.OrderByOpts))
+
+		for _, col := range cfg.OrderBy {
+			cols = append(cols, OrderByOpts{Key: col})
+		}
+
 		f.ordering = true
 		f.orderer = *new_orderer(orderer_opts{
-			cols: cfg.OrderBy,
+			cols:         append(cols, cfg.OrderByOpts...),
+			max_keys:     cfg.OrderByMax,
+			default_keys: cfg.OrderByDefault,
 		})
 	}
 
 	return &f
 }
 
+// ValidateAndConstruct returns the select query with the values inlined as escaped literals
 func (f *filters) ValidateAndConstruct(v url.Values, lang string) (string, error) {
+	var q, err = f.construct(v, lang, f.new_ctx(false))
 
-	var errs = make(FilterErrs, 0, f.len)
+	if err != nil {
+		return "", err
+	}
 
-	var conds = ""
-	var cond string
+	return f.sql_select + q.select_where() + q.order_by + q.limit_offset, nil
+}
 
-	var err error
+// ValidateAndConstructWithCount returns the select and the count queries with the values inlined as escaped literals
+func (f *filters) ValidateAndConstructWithCount(v url.Values, lang string) (string, string, error) {
+	var q, err = f.construct(v, lang, f.new_ctx(false))
 
-	for _, f := range f.filters {
-		if cond, err = f.validate_and_construct(v, lang); err != nil {
-			errs = append(errs, err)
-			continue
-		}
+	if err != nil {
+		return "", "", err
+	}
 
-		if cond != "" {
-			conds += " AND " + cond
-		}
+	return f.sql_select + q.select_where() + q.order_by + q.limit_offset, f.sql_count + q.where, nil
+}
 
+// ValidateAndConstructWithArgs returns the select query with placeholders and its arguments in order
+func (f *filters) ValidateAndConstructWithArgs(v url.Values, lang string) (string, []any, error) {
+	var ctx = f.new_ctx(true)
+	var q, err = f.construct(v, lang, ctx)
+
+	if err != nil {
+		return "", nil, err
 	}
 
-	var order_by string
+	return f.sql_select + q.select_where() + q.order_by + q.limit_offset, ctx.args, nil
+}
 
-	if f.ordering {
-		if order_by, err = f.orderer.order(v, lang); err != nil {
-			errs = append(errs, err)
-		}
+// ValidateAndConstructWithCountAndArgs returns the select and the count queries with placeholders,
+// each one with its own arguments (the count query does not have the arguments of limit and offset)
+func (f *filters) ValidateAndConstructWithCountAndArgs(v url.Values, lang string) (string, []any, string, []any, error) {
+	var ctx = f.new_ctx(true)
+	var q, err = f.construct(v, lang, ctx)
+
+	if err != nil {
+		return "", nil, "", nil, err
 	}
 
-	var limit_offset string
+	var count_args = ctx.args[:q.where_args_len:q.where_args_len]
 
-	if f.paginate {
-		if limit_offset, err = f.paginator.paginate(v, lang); err != nil {
-			errs = append(errs, err)
-		}
-	}
+	return f.sql_select + q.select_where() + q.order_by + q.limit_offset, ctx.args, f.sql_count + q.where, count_args, nil
+}
 
-	if len(errs) > 0 {
-		return "", &errs
+func (f *filters) new_ctx(parameterize bool) *SqlCtx {
+	return &SqlCtx{
+		dialect:      f.dialect,
+		quote_idents: f.quote,
+		parameterize: parameterize,
+		placeholder:  f.placeholder,
+		args:         []any{},
+		now:          f.clock.Now(),
 	}
+}
 
-	var query_suffix = ""
+type query_parts struct {
+	where          string
+	where_args_len int
+	seek           string // cursor condition (select query only)
+	order_by       string
+	limit_offset   string
+}
 
-	if len(conds) > 0 {
-		conds = conds[5:]
-		query_suffix = " WHERE " + conds
+func (q *query_parts) select_where() string {
+	if q.seek == "" {
+		return q.where
 	}
 
-	if order_by != "" {
-		query_suffix += " " + order_by
+	if q.where == "" {
+		return " WHERE " + q.seek
 	}
 
-	if limit_offset != "" {
-		query_suffix += " " + limit_offset
+	return q.where + " AND " + q.seek
+}
+
+func (f *filters) sort_keys(v url.Values, lang string) ([]sort_key, error) {
+	var keys []sort_key
+	var err error
+
+	if f.ordering {
+		if keys, err = f.orderer.sort_keys(v, lang); err != nil {
+			return nil, err
+		}
 	}
 
-	return f.sql_select + query_suffix, nil
+	// every sort should be deterministic (the pages should not overlap)
+	if f.tie_breaker != "" {
+		keys = append_tie_breaker(keys, f.tie_breaker)
+	}
 
+	return keys, nil
 }
 
-func (f *filters) ValidateAndConstructWithCount(v url.Values, lang string) (string, string, error) {
+func (f *filters) construct(v url.Values, lang string, ctx *SqlCtx) (*query_parts, error) {
 	var errs = make(FilterErrs, 0, f.len)
 
-	var conds = ""
-	var cond string
+	var conds = f.construct_conds(v, lang, ctx, nil, &errs)
 
 	var err error
 
-	for _, f := range f.filters {
-		if cond, err = f.validate_and_construct(v, lang); err != nil {
+	if f.grouping {
+		var root *group
+		if root, err = f.group_opts.parse_groups(v, lang, func(key string, vals []string) bool {
+			return f.claims(key, vals, lang)
+		}); err != nil {
 			errs = append(errs, err)
-			continue
+		} else if root != nil {
+			conds = append(conds, f.construct_group_conds(root, lang, ctx, nil, &errs)...)
 		}
+	}
 
-		if cond != "" {
-			conds += " AND " + cond
-		}
+	var q = query_parts{
+		where_args_len: len(ctx.args),
+	}
 
+	if len(conds) > 0 {
+		q.where = " WHERE " + strings.Join(conds, " AND ")
 	}
 
-	var order_by string
+	var keys []sort_key
+	var sort_err error
 
-	if f.ordering {
-		if order_by, err = f.orderer.order(v, lang); err != nil {
-			errs = append(errs, err)
-		} else {
-			order_by = " " + order_by
-		}
+	if keys, sort_err = f.sort_keys(v, lang); sort_err != nil {
+		errs = append(errs, sort_err)
+	} else if len(keys) > 0 {
+		q.order_by = " " + construct_order_by(keys, ctx)
 	}
 
-	var limit_offset string
-
-	if f.paginate {
-		if limit_offset, err = f.paginator.paginate(v, lang); err != nil {
+	if f.cursor {
+		// the cursor cannot be checked against an invalid sort
+		if sort_err == nil {
+			if q.seek, q.limit_offset, err = f.paginator.paginate_cursor(v, lang, ctx, keys); err != nil {
+				errs = append(errs, err)
+			} else if q.limit_offset != "" {
+				q.limit_offset = " " + q.limit_offset
+			}
+		}
+	} else if f.paginate {
+		if q.limit_offset, err = f.paginator.paginate(v, lang, ctx, q.order_by != ""); err != nil {
 			errs = append(errs, err)
-		} else {
-			limit_offset = " " + limit_offset
+		} else if q.limit_offset != "" {
+			q.limit_offset = " " + q.limit_offset
 		}
 	}
 
 	if len(errs) > 0 {
-		return "", "", &errs
+		if f.ar_digits {
+			localize_errs_digits(errs, primary_lang(lang) == "ar")
+		}
+		return nil, &errs
 	}
 
-	var where string
+	return &q, nil
+}
 
-	if len(conds) > 0 {
-		conds = conds[5:]
-		where = " WHERE " + conds
+// localize_errs_digits marks the errors with FilterConfigs.ArabicDigits (for NewProblem)
+// and writes the numbers of the messages in Eastern Arabic digits if ar is true
+func localize_errs_digits(errs FilterErrs, ar bool) {
+	for _, err := range errs {
+		if f_err, ok := err.(*FilterErr); ok {
+			f_err.ar_digits = true
+			if ar {
+				f_err.Message = localize_digits(f_err.Message)
+			}
+		}
 	}
-
-	return f.sql_select + where + order_by + limit_offset, f.sql_count + where, nil
 }
@@ -0,0 +1,76 @@
+package filter
+
+import (
+	"net/url"
+	"strings"
+)
+
+// eq, null
+
+var bool_vals = map[string]bool{
+	"true":  true,
+	"1":     true,
+	"yes":   true,
+	"نعم":   true,
+	"صح":    true,
+	"صحيح":  true,
+	"false": false,
+	"0":     false,
+	"no":    false,
+	"لا":    false,
+	"خطأ":   false,
+	"خاطئ":  false,
+}
+
+type bool_filter struct {
+	key       string
+	col_alias string
+	null_opt  bool
+}
+
+type BoolFilterOpts struct {
+	Key      string
+	ColAlias string
+	NullOpt  bool
+}
+
+func NewBoolFilter(opts BoolFilterOpts) *bool_filter {
+	if opts.ColAlias == "" {
+		opts.ColAlias = opts.Key
+	}
+
+	return &bool_filter{
+		key:       opts.Key,
+		col_alias: opts.ColAlias,
+		null_opt:  opts.NullOpt,
+	}
+}
+
+func (b *bool_filter) ValidateAndConstruct(
+	v url.Values,
+	lang string,
+	ctx *SqlCtx,
+) (string, error) {
+
+	var cond string
+
+	if input, ok := get_first_el_if_exists(v, b.key+"[eq]"); ok {
+		var val, valid = bool_vals[strings.ToLower(strings.TrimSpace(normalize_digits(input)))]
+
+		if !valid {
+			return "", new_err(b.key, input, invalid_bool_err(), lang, "eq")
+		}
+
+		cond = ctx.Ident(b.col_alias) + "=" + ctx.Bind(val)
+	}
+
+	if b.null_opt {
+		cond = with_null_cond(v, b.key, ctx.Ident(b.col_alias), cond)
+	}
+
+	return cond, nil
+}
+
+func invalid_bool_err() message {
+	return message{code: "bool.invalid"}
+}
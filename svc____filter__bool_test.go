@@ -0,0 +1,179 @@
+package filter_test
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/MaSTeR2W/filter"
+)
+
+func TestBoolFilter(t *testing.T) {
+	const (
+		LANG_AR = "ar"
+		LANG_EN = "en"
+	)
+
+	var sql = "SELECT * FROM users"
+
+	var new_filters = func(dialect filter.Dialect) interface {
+		ValidateAndConstruct(url.Values, string) (string, error)
+		ValidateAndConstructWithArgs(url.Values, string) (string, []any, error)
+	} {
+		return filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect: sql,
+				Dialect:   dialect,
+			},
+			filter.NewBoolFilter(filter.BoolFilterOpts{
+				Key:      "active",
+				ColAlias: "is_active",
+				NullOpt:  true,
+			}),
+		)
+	}
+
+	var fs = new_filters(filter.Postgres)
+
+	var test_eq = func(t *testing.T) {
+		var inputs = map[string]string{
+			"true":  "TRUE",
+			"1":     "TRUE",
+			"Yes":   "TRUE",
+			"نعم":   "TRUE",
+			"صحيح":  "TRUE",
+			"FALSE": "FALSE",
+			"0":     "FALSE",
+			"١":     "TRUE",
+			"٠":     "FALSE",
+			"no":    "FALSE",
+			"لا":    "FALSE",
+			"خطأ":   "FALSE",
+		}
+
+		for input, lit := range inputs {
+			var v = url.Values{
+				"active[eq]": []string{input},
+			}
+
+			var query, err = fs.ValidateAndConstruct(v, LANG_AR)
+
+			if err != nil {
+				t.Error(input, err)
+				continue
+			}
+
+			if query != sql+" WHERE is_active="+lit {
+				t.Error("invalid query:", query)
+			}
+		}
+	}
+
+	t.Run("test_eq", test_eq)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_dialects = func(t *testing.T) {
+		var v = url.Values{
+			"active[eq]": []string{"true"},
+		}
+
+		for _, dialect := range []filter.Dialect{filter.SQLite, filter.SQLServer} {
+			var query, err = new_filters(dialect).ValidateAndConstruct(v, LANG_AR)
+
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+
+			if query != sql+" WHERE is_active=1" {
+				t.Error("invalid query:", query)
+			}
+		}
+
+		var query, args, err = fs.ValidateAndConstructWithArgs(v, LANG_AR)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != sql+" WHERE is_active=$1" {
+			t.Error("invalid query:", query)
+		}
+
+		if !reflect.DeepEqual(args, []any{true}) {
+			t.Error("invalid args:", args)
+		}
+	}
+
+	t.Run("test_dialects", test_dialects)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_null = func(t *testing.T) {
+		var cases = []struct {
+			v     url.Values
+			query string
+		}{
+			{url.Values{"active[null]": []string{"1"}}, sql + " WHERE is_active IS NULL"},
+			{url.Values{"active[null]": []string{"0"}}, sql + " WHERE is_active IS NOT NULL"},
+			{url.Values{"active[null]": []string{"٠"}}, sql + " WHERE is_active IS NOT NULL"},
+			{url.Values{"active[null]": []string{"1"}, "active[eq]": []string{"no"}}, sql + " WHERE (is_active=FALSE OR is_active IS NULL)"},
+			{url.Values{"active[null]": []string{"0"}, "active[eq]": []string{"no"}}, sql + " WHERE is_active=FALSE"},
+		}
+
+		for _, c := range cases {
+			var query, err = fs.ValidateAndConstruct(c.v, LANG_AR)
+
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+
+			if query != c.query {
+				t.Error("invalid query:", query)
+			}
+		}
+	}
+
+	t.Run("test_null", test_null)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_invalid = func(t *testing.T) {
+		var v = url.Values{
+			"active[eq]": []string{"maybe"},
+		}
+
+		var _, err = fs.ValidateAndConstruct(v, LANG_EN)
+
+		if err == nil {
+			t.Error("should throw error")
+			return
+		}
+
+		var f_err = (*err.(*filter.FilterErrs))[0].(*filter.FilterErr)
+
+		if f_err.Key != "active" || f_err.Value != "maybe" || f_err.Message != "The value should be true or false" {
+			t.Error("invalid error:", f_err)
+		}
+	}
+
+	t.Run("test_invalid", test_invalid)
+}
@@ -4,24 +4,18 @@ import (
 	"net/url"
 	"slices"
 	"strconv"
-	"strings"
 )
 
-func exceed_num_of_available_opts_err(opts_num string, lang string) string {
-	if lang == "ar" {
-		return "لا يمكن تجاوز عدد الخيارات المتاحة (" + opts_num + ")"
-	}
-	return "The number of options available (" + opts_num + ") cannot be exceeded"
+func exceed_num_of_available_opts_err(opts_num int) message {
+	return message{code: "option.too_many", params: map[string]any{"max": opts_num}}
 }
 
 type checkbox_int_filter struct {
-	key        string
-	col_alias  string
-	null_opt   bool
-	opts       []int
-	s_opts     string
-	opts_num   int
-	s_opts_num string
+	key       string
+	col_alias string
+	null_opt  bool
+	opts      []int
+	opts_num  int
 }
 
 type CheckboxIntFilterOpts struct {
@@ -45,24 +39,20 @@ func NewCheckboxIntFilter(
 		key:       opts.Key,
 		col_alias: opts.ColAlias,
 		opts:      opts.Opts,
-		s_opts: strings.Join(
-			sl_of_int_to_sl_of_str[int](opts.Opts),
-			", ",
-		),
-		null_opt:   opts.NullOpt,
-		opts_num:   opts_num,
-		s_opts_num: strconv.Itoa(opts_num),
+		null_opt:  opts.NullOpt,
+		opts_num:  opts_num,
 	}
 }
 
-func (c *checkbox_int_filter) validate_and_construct(
+func (c *checkbox_int_filter) ValidateAndConstruct(
 	v url.Values,
 	lang string,
+	ctx *SqlCtx,
 ) (string, error) {
 	var cond string
 	var err error
 
-	if cond, err = c.validate_and_construct_vals(v, lang); err != nil {
+	if cond, err = c.validate_and_construct_vals(v, lang, ctx); err != nil {
 		return "", err
 	}
 
@@ -74,10 +64,10 @@ func (c *checkbox_int_filter) validate_and_construct(
 	var ok bool
 	if null, ok = get_first_el_if_exists(v, c.key+"[null]"); ok {
 
-		if null == "0" {
-			null = c.col_alias + " IS NOT NULL"
+		if normalize_digits(null) == "0" {
+			null = ctx.Ident(c.col_alias) + " IS NOT NULL"
 		} else {
-			null = c.col_alias + " IS NULL"
+			null = ctx.Ident(c.col_alias) + " IS NULL"
 		}
 
 		if len(cond) > 0 {
@@ -93,6 +83,7 @@ func (c *checkbox_int_filter) validate_and_construct(
 func (c *checkbox_int_filter) validate_and_construct_vals(
 	v url.Values,
 	lang string,
+	ctx *SqlCtx,
 ) (string, error) {
 	var vals []string
 	var ok bool
@@ -108,82 +99,61 @@ func (c *checkbox_int_filter) validate_and_construct_vals(
 		return "", nil
 	}
 
+	var nums []any
 	var err error
-	if vals, err = c.validate_arr_of_int(vals, lang); err != nil {
+	if nums, err = c.validate_arr_of_int(vals, lang); err != nil {
 		return "", err
 	}
 
-	return c.col_alias + op + "(" + strings.Join(vals, ",") + ")", nil
+	return ctx.Ident(c.col_alias) + op + "(" + ctx.BindList(nums) + ")", nil
 }
 
 func (c *checkbox_int_filter) validate_arr_of_int(
 	v []string,
 	lang string,
-) ([]string, error) {
+) ([]any, error) {
 
 	var err error
 
 	if len(v) > c.opts_num {
-		return nil, &FilterErr{
-			Key:     c.key,
-			Value:   v,
-			Message: exceed_num_of_available_opts_err(c.s_opts_num, lang),
-		}
+		return nil, new_err(c.key, v, exceed_num_of_available_opts_err(c.opts_num), lang)
 	}
 
-	var safe_int_str_arr = make([]string, 0, len(v))
+	var nums = make([]any, 0, len(v))
 
 	var num int
 	for idx, el := range v {
 
-		if num, err = strconv.Atoi(el); err != nil {
-			return nil, &FilterErr{
-				Key:     c.key,
-				Value:   el,
-				Path:    []any{idx},
-				Message: entry_is_not_num_err(lang),
-			}
+		if num, err = strconv.Atoi(normalize_digits(el)); err != nil {
+			return nil, new_err(c.key, el, entry_is_not_num_err(), lang, idx)
 		}
 
 		if !slices.Contains(c.opts, num) {
-			return nil, &FilterErr{
-				Key:     c.key,
-				Value:   el,
-				Path:    []any{idx},
-				Message: num_is_not_one_of(c.s_opts, lang),
-			}
+			return nil, new_err(c.key, el, num_is_not_one_of(c.opts), lang, idx)
 		}
 
 		// may be strconv.Atoi has unexpected behaviour
 		// so cannot trust the original string value
-		safe_int_str_arr = append(safe_int_str_arr, strconv.Itoa(num))
+		nums = append(nums, num)
 	}
 
-	return safe_int_str_arr, nil
+	return nums, nil
 }
 
-func entry_is_not_num_err(lang string) string {
-	if lang == "ar" {
-		return "هذا ليس عددا"
-	}
-	return "This is not a number"
+func entry_is_not_num_err() message {
+	return message{code: "option.not_a_number"}
 }
 
-func num_is_not_one_of(one_of string, lang string) string {
-	if lang == "ar" {
-		return "يجب أن يكون العدد واحد من: (" + one_of + ")"
-	}
-	return "The number should be one of: (" + one_of + ")"
+func num_is_not_one_of(one_of []int) message {
+	return message{code: "option.number_not_allowed", params: map[string]any{"options": one_of}}
 }
 
 type checkbox_str_filter struct {
-	key        string
-	col_alias  string
-	null_opt   bool
-	opts       []string
-	s_opts     string
-	opts_num   int
-	s_opts_num string
+	key       string
+	col_alias string
+	null_opt  bool
+	opts      []string
+	opts_num  int
 }
 
 type CheckboxStrFilterOpts struct {
@@ -202,34 +172,24 @@ func NewCheckboxStrFilter(
 
 	var opts_num = len(opts.Opts)
 
-	var s_opts = ""
-	for _, opt := range opts.Opts {
-		s_opts += ", " + opt
-	}
-
-	if len(s_opts) > 0 {
-		s_opts = s_opts[2:]
-	}
-
 	return &checkbox_str_filter{
-		key:        opts.Key,
-		col_alias:  opts.ColAlias,
-		null_opt:   opts.NullOpt,
-		opts:       opts.Opts,
-		s_opts:     s_opts,
-		opts_num:   opts_num,
-		s_opts_num: strconv.Itoa(opts_num),
+		key:       opts.Key,
+		col_alias: opts.ColAlias,
+		null_opt:  opts.NullOpt,
+		opts:      opts.Opts,
+		opts_num:  opts_num,
 	}
 }
 
-func (c *checkbox_str_filter) validate_and_construct(
+func (c *checkbox_str_filter) ValidateAndConstruct(
 	v url.Values,
 	lang string,
+	ctx *SqlCtx,
 ) (string, error) {
 	var cond string
 	var err error
 
-	if cond, err = c.validate_and_construct_vals(v, lang); err != nil {
+	if cond, err = c.validate_and_construct_vals(v, lang, ctx); err != nil {
 		return "", err
 	}
 
@@ -241,10 +201,10 @@ func (c *checkbox_str_filter) validate_and_construct(
 	var ok bool
 	if null, ok = get_first_el_if_exists(v, c.key+"[null]"); ok {
 
-		if null == "0" {
-			null = c.col_alias + " IS NOT NULL"
+		if normalize_digits(null) == "0" {
+			null = ctx.Ident(c.col_alias) + " IS NOT NULL"
 		} else {
-			null = c.col_alias + " IS NULL"
+			null = ctx.Ident(c.col_alias) + " IS NULL"
 		}
 
 		if len(cond) > 0 {
@@ -260,6 +220,7 @@ func (c *checkbox_str_filter) validate_and_construct(
 func (c *checkbox_str_filter) validate_and_construct_vals(
 	v url.Values,
 	lang string,
+	ctx *SqlCtx,
 ) (string, error) {
 	var vals []string
 	var ok bool
@@ -275,42 +236,35 @@ func (c *checkbox_str_filter) validate_and_construct_vals(
 		return "", nil
 	}
 
+	var strs []any
 	var err error
-	if vals, err = c.validate_arr_of_str(vals, lang); err != nil {
+	if strs, err = c.validate_arr_of_str(vals, lang); err != nil {
 		return "", err
 	}
 
-	return c.col_alias + op + "(" + strings.Join(vals, ",") + ")", nil
+	return ctx.Ident(c.col_alias) + op + "(" + ctx.BindList(strs) + ")", nil
 }
 
 func (c *checkbox_str_filter) validate_arr_of_str(
 	v []string,
 	lang string,
-) ([]string, error) {
+) ([]any, error) {
 
-	var escaped_strs = make([]string, 0, len(v))
+	var strs = make([]any, 0, len(v))
 
 	for idx, el := range v {
 
 		if !slices.Contains(c.opts, el) {
-			return nil, &FilterErr{
-				Key:     c.key,
-				Value:   el,
-				Path:    []any{idx},
-				Message: str_is_not_one_of(c.s_opts, lang),
-			}
+			return nil, new_err(c.key, el, str_is_not_one_of(c.opts), lang, idx)
 		}
 
-		escaped_strs = append(escaped_strs, to_escaped_string(el))
+		strs = append(strs, el)
 
 	}
 
-	return escaped_strs, nil
+	return strs, nil
 }
 
-func str_is_not_one_of(one_of string, lang string) string {
-	if lang == "ar" {
-		return "يجب أن يكون الخيار واحد من: (" + one_of + ")"
-	}
-	return "The option should be one of: (" + one_of + ")"
+func str_is_not_one_of(one_of []string) message {
+	return message{code: "option.not_allowed", params: map[string]any{"options": one_of}}
 }
@@ -44,6 +44,26 @@ func TestCheckboxIntFilter(t *testing.T) {
 
 	t.Run("test_checkbox_int_filter_in", test_checkbox_int_filter_in)
 
+	var test_checkbox_int_filter_arabic_digits = func(t *testing.T) {
+		var v = url.Values{
+			"userStatus[in]": []string{"٠", "۲"},
+		}
+
+		var query, err = fs.ValidateAndConstruct(v, "")
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != sql+" WHERE userStatus IN (0,2)" {
+			t.Error(query)
+			return
+		}
+	}
+
+	t.Run("test_checkbox_int_filter_arabic_digits", test_checkbox_int_filter_arabic_digits)
+
 	var test_checkbox_int_filter_nin = func(t *testing.T) {
 		var v = url.Values{
 			"userStatus[nin]": []string{"0", "2"},
@@ -161,6 +181,22 @@ func TestCheckboxIntFilter(t *testing.T) {
 			t.Error(query)
 			return
 		}
+
+		// Eastern Arabic zero
+		v = url.Values{
+			"userStatus[null]": []string{"٠"},
+		}
+		query, err = fs.ValidateAndConstruct(v, "")
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != sql+" WHERE alias IS NOT NULL" {
+			t.Error(query)
+			return
+		}
 	}
 
 	t.Run("test_null_allowed", test_null_allowed)
@@ -0,0 +1,333 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cursor is the decoded form of ($cursor), it holds the sort keys
+// (prefixed with "-" when descending) and the values of the last row
+type cursor struct {
+	Keys  []string `json:"k"`
+	Vals  []any    `json:"v"`
+	Types []string `json:"t,omitempty"` // the type of each value that JSON does not keep (cursor_time, cursor_decimal)
+}
+
+// the types of the cursor values, the other values are strings, numbers or bools
+const (
+	cursor_time    = "time"    // RFC 3339 string, bound as time.Time
+	cursor_decimal = "decimal" // decimal string, inlined as an exact number
+)
+
+func cursor_keys(keys []sort_key) []string {
+	var c_keys = make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key.desc {
+			c_keys = append(c_keys, "-"+key.key)
+		} else {
+			c_keys = append(c_keys, key.key)
+		}
+	}
+	return c_keys
+}
+
+// EncodeCursor returns the ($cursor) of the page that comes after the last row of the current page,
+// row should have the value of each sort key (including the TieBreaker) of that last row,
+// the values may be strings, numbers, bools, time.Time and the exact decimals (json.Number or *big.Float)
+func (f *filters) EncodeCursor(v url.Values, row map[string]any, lang string) (string, error) {
+	var keys, err = f.sort_keys(v, lang)
+
+	if err != nil {
+		return "", err
+	}
+
+	var c = cursor{
+		Keys:  cursor_keys(keys),
+		Vals:  make([]any, 0, len(keys)),
+		Types: make([]string, 0, len(keys)),
+	}
+
+	var typed = false
+
+	for _, key := range keys {
+		var val, ok = row[key.key]
+		if !ok || val == nil {
+			return "", errors.New("filter: missing cursor value of (" + key.key + ")")
+		}
+
+		var typ string
+		if val, typ, err = cursor_val(val); err != nil {
+			return "", errors.New("filter: invalid cursor value of (" + key.key + "), " + err.Error())
+		}
+
+		if typ != "" {
+			typed = true
+		}
+
+		c.Types = append(c.Types, typ)
+
+		// the value is checked here, otherwise the cursor would be rejected in the next request
+		var js_val []byte
+		if js_val, err = json.Marshal(val); err != nil {
+			return "", err
+		}
+
+		if !is_cursor_val(js_val) {
+			return "", errors.New("filter: unsupported cursor value of (" + key.key + "), it should be a string, a number or a bool")
+		}
+
+		c.Vals = append(c.Vals, val)
+	}
+
+	// the cursors of the untyped values stay short
+	if !typed {
+		c.Types = nil
+	}
+
+	var js []byte
+	if js, err = json.Marshal(c); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(js), nil
+}
+
+// cursor_val returns the JSON value of val and its cursor type (if JSON does not keep it)
+func cursor_val(val any) (any, string, error) {
+	switch t := val.(type) {
+	case time.Time:
+		return t.Format(time.RFC3339Nano), cursor_time, nil
+	case *time.Time:
+		if t != nil {
+			return t.Format(time.RFC3339Nano), cursor_time, nil
+		}
+	case json.Number:
+		if _, canonical, ok := parse_decimal(t.String()); ok {
+			return canonical, cursor_decimal, nil
+		}
+		return nil, "", errors.New("it is not a decimal")
+	case *big.Float:
+		if t != nil && !t.IsInf() {
+			return t.Text('f', -1), cursor_decimal, nil
+		}
+	}
+
+	return val, "", nil
+}
+
+// is_cursor_val reports whether the JSON value is a string, a number or a bool
+func is_cursor_val(js []byte) bool {
+	if len(js) == 0 {
+		return false
+	}
+
+	switch c := js[0]; {
+	case c == '"', c == 't', c == 'f', c == '-', c >= '0' && c <= '9':
+		return true
+	}
+
+	return false
+}
+
+func decode_cursor(token string, keys []sort_key) ([]any, bool) {
+	var js, err = base64.RawURLEncoding.DecodeString(token)
+
+	if err != nil {
+		return nil, false
+	}
+
+	var dec = json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+
+	var c cursor
+	if err = dec.Decode(&c); err != nil {
+		return nil, false
+	}
+
+	// the cursor belongs to another sort
+	if !slices.Equal(c.Keys, cursor_keys(keys)) || len(c.Vals) != len(keys) {
+		return nil, false
+	}
+
+	if c.Types != nil && len(c.Types) != len(c.Vals) {
+		return nil, false
+	}
+
+	for idx, val := range c.Vals {
+		var typ = ""
+		if c.Types != nil {
+			typ = c.Types[idx]
+		}
+
+		var ok bool
+		if c.Vals[idx], ok = decode_cursor_val(val, typ); !ok {
+			return nil, false
+		}
+	}
+
+	return c.Vals, true
+}
+
+// decode_cursor_val returns the value to bind of the decoded JSON value:
+// time.Time for cursor_time (Dialect.Timestamp), an exact number for cursor_decimal
+// and for the integers that do not fit in int, int or float64 for the other numbers
+func decode_cursor_val(val any, typ string) (any, bool) {
+	switch typ {
+	case cursor_time:
+		var s, ok = val.(string)
+		if !ok {
+			return nil, false
+		}
+
+		var t, err = time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, false
+		}
+		return t, true
+
+	case cursor_decimal:
+		var s, ok = val.(string)
+		if !ok {
+			return nil, false
+		}
+		return decimal_literal(s)
+
+	case "":
+	default:
+		return nil, false
+	}
+
+	switch t := val.(type) {
+	case string, bool:
+		return t, true
+	case json.Number:
+		if num, err := strconv.Atoi(t.String()); err == nil {
+			return num, true
+		}
+
+		// an integer that does not fit in int
+		if !strings.ContainsAny(t.String(), ".eE") {
+			return decimal_literal(t.String())
+		}
+
+		var f_num, err = t.Float64()
+		if err != nil {
+			return nil, false
+		}
+		return f_num, true
+	}
+
+	return nil, false
+}
+
+// decimal_literal returns s (which is inlined in the query) after validating it
+func decimal_literal(s string) (any, bool) {
+	var _, canonical, ok = parse_decimal(s)
+	if !ok {
+		return nil, false
+	}
+	return raw_literal{sql: canonical, arg: canonical}, true
+}
+
+// paginate_cursor returns the seek condition and the limit
+func (p *paginator) paginate_cursor(v url.Values, lang string, ctx *SqlCtx, keys []sort_key) (string, string, error) {
+	var s_cursor, ok_cursor = get_first_el_if_exists(v, "$cursor")
+	var s_limit, ok_limit = get_first_el_if_exists(v, "$limit")
+
+	if !ok_cursor && !ok_limit {
+		return "", "", nil
+	}
+
+	if !ok_limit {
+		return "", "", new_err("$limit", OmitVal, missing_limit_err(), lang)
+	}
+
+	var limit, err = strconv.Atoi(normalize_digits(s_limit))
+
+	if err != nil {
+		return "", "", new_err("$limit", s_limit, invalid_limit_err(), lang)
+	}
+
+	if err = p.validate_limit(limit, lang); err != nil {
+		return "", "", err
+	}
+
+	var seek string
+
+	if ok_cursor {
+		var vals []any
+		if vals, ok_cursor = decode_cursor(s_cursor, keys); !ok_cursor {
+			return "", "", new_err("$cursor", s_cursor, invalid_cursor_err(), lang)
+		}
+
+		seek = construct_seek(keys, vals, ctx)
+	}
+
+	return seek, ctx.dialect.Limit(ctx.Bind, limit, true), nil
+}
+
+// construct_seek returns the condition of the rows that come after vals:
+//
+//	(a,b)>(1,2)                  when all the keys have the same direction
+//	(a>1 OR (a=1 AND b<2))       otherwise (or when the dialect does not support row values)
+func construct_seek(keys []sort_key, vals []any, ctx *SqlCtx) string {
+	var uniform = true
+	for _, key := range keys[1:] {
+		if key.desc != keys[0].desc {
+			uniform = false
+			break
+		}
+	}
+
+	if len(keys) == 1 || (uniform && ctx.dialect.RowValues()) {
+		var cols = make([]string, 0, len(keys))
+		for _, key := range keys {
+			cols = append(cols, ctx.Ident(key.expr))
+		}
+
+		if len(keys) == 1 {
+			return cols[0] + seek_op(keys[0]) + ctx.Bind(vals[0])
+		}
+
+		return "(" + strings.Join(cols, ",") + ")" + seek_op(keys[0]) + "(" + ctx.BindList(vals) + ")"
+	}
+
+	var ors = make([]string, 0, len(keys))
+
+	for idx, key := range keys {
+		var ands = make([]string, 0, idx+1)
+
+		for prev := 0; prev < idx; prev++ {
+			ands = append(ands, ctx.Ident(keys[prev].expr)+"="+ctx.Bind(vals[prev]))
+		}
+
+		ands = append(ands, ctx.Ident(key.expr)+seek_op(key)+ctx.Bind(vals[idx]))
+
+		if len(ands) == 1 {
+			ors = append(ors, ands[0])
+		} else {
+			ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+		}
+	}
+
+	return "(" + strings.Join(ors, " OR ") + ")"
+}
+
+func seek_op(key sort_key) string {
+	if key.desc {
+		return "<"
+	}
+	return ">"
+}
+
+func invalid_cursor_err() message {
+	return message{code: "cursor.invalid"}
+}
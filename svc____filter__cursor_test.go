@@ -0,0 +1,410 @@
+package filter_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MaSTeR2W/filter"
+)
+
+func TestCursorPagination(t *testing.T) {
+	const (
+		LANG_AR = "ar"
+		LANG_EN = "en"
+	)
+
+	var fs = filter.NewFilters(
+		filter.FilterConfigs{
+			SqlSelect:  "SELECT * FROM users",
+			SqlCount:   "SELECT COUNT(*) AS count FROM users",
+			Paginate:   true,
+			LimitMax:   20,
+			OrderBy:    []string{"firstName", "lastName"},
+			Cursor:     true,
+			TieBreaker: "id",
+		},
+		filter.NewStrFilter(filter.StrFilterOpts{
+			Key: "firstName",
+		}),
+	)
+
+	var test_first_page = func(t *testing.T) {
+		var v = url.Values{
+			"$order_by": []string{"lastName"},
+			"$arrange":  []string{"DESC"},
+			"$limit":    []string{"10"},
+		}
+
+		var query, err = fs.ValidateAndConstruct(v, LANG_AR)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != "SELECT * FROM users ORDER BY lastName DESC, id DESC LIMIT 10" {
+			t.Error("invalid query:", query)
+		}
+	}
+
+	t.Run("test_first_page", test_first_page)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_next_page = func(t *testing.T) {
+		var v = url.Values{
+			"firstName[eq]": []string{"marwan"},
+			"$order_by":     []string{"lastName"},
+			"$arrange":      []string{"DESC"},
+			"$limit":        []string{"10"},
+		}
+
+		var cursor, err = fs.EncodeCursor(v, map[string]any{
+			"lastName": "o'neil",
+			"id":       42,
+		}, LANG_AR)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		v.Set("$cursor", cursor)
+
+		var sel, sel_args, count, count_args, c_err = fs.ValidateAndConstructWithCountAndArgs(v, LANG_AR)
+
+		if c_err != nil {
+			t.Error(c_err)
+			return
+		}
+
+		if sel != "SELECT * FROM users WHERE firstName=? AND (lastName,id)<(?,?) ORDER BY lastName DESC, id DESC LIMIT ?" {
+			t.Error("invalid sel:", sel)
+		}
+
+		if !reflect.DeepEqual(sel_args, []any{"marwan", "o'neil", 42, 10}) {
+			t.Error("invalid sel args:", sel_args)
+		}
+
+		if count != "SELECT COUNT(*) AS count FROM users WHERE firstName=?" {
+			t.Error("invalid count:", count)
+		}
+
+		if !reflect.DeepEqual(count_args, []any{"marwan"}) {
+			t.Error("invalid count args:", count_args)
+		}
+	}
+
+	t.Run("test_next_page", test_next_page)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_without_row_values = func(t *testing.T) {
+		var fs = filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect:  "SELECT * FROM users",
+				Paginate:   true,
+				OrderBy:    []string{"lastName"},
+				Cursor:     true,
+				TieBreaker: "id",
+				Dialect:    filter.SQLServer,
+			},
+		)
+
+		var v = url.Values{
+			"$order_by": []string{"lastName"},
+			"$limit":    []string{"5"},
+		}
+
+		var cursor, err = fs.EncodeCursor(v, map[string]any{
+			"lastName": "smith",
+			"id":       7,
+		}, LANG_EN)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		v.Set("$cursor", cursor)
+
+		var query string
+		if query, err = fs.ValidateAndConstruct(v, LANG_EN); err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != "SELECT * FROM users WHERE (lastName>N'smith' OR (lastName=N'smith' AND id>7)) ORDER BY lastName ASC, id ASC OFFSET 0 ROWS FETCH NEXT 5 ROWS ONLY" {
+			t.Error("invalid query:", query)
+		}
+	}
+
+	t.Run("test_without_row_values", test_without_row_values)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_invalid_cursor = func(t *testing.T) {
+		var v = url.Values{
+			"$order_by": []string{"lastName"},
+			"$limit":    []string{"10"},
+		}
+
+		var cursor, err = fs.EncodeCursor(v, map[string]any{
+			"lastName": "smith",
+			"id":       42,
+		}, LANG_EN)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		// the cursor does not belong to this sort
+		v.Set("$order_by", "firstName")
+		v.Set("$cursor", cursor)
+
+		_, err = fs.ValidateAndConstruct(v, LANG_EN)
+
+		if err == nil {
+			t.Error("should throw error")
+			return
+		}
+
+		var f_err = (*err.(*filter.FilterErrs))[0].(*filter.FilterErr)
+
+		if f_err.Key != "$cursor" || f_err.Message != "The cursor is invalid" {
+			t.Error("invalid error:", f_err)
+		}
+
+		v = url.Values{
+			"$cursor": []string{"not-a-cursor"},
+		}
+
+		_, err = fs.ValidateAndConstruct(v, LANG_AR)
+
+		if err == nil {
+			t.Error("should throw error")
+			return
+		}
+
+		f_err = (*err.(*filter.FilterErrs))[0].(*filter.FilterErr)
+
+		if f_err.Key != "$limit" || f_err.Message != "الحد مفقود" {
+			t.Error("invalid error:", f_err)
+		}
+	}
+
+	t.Run("test_invalid_cursor", test_invalid_cursor)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_missing_row_value = func(t *testing.T) {
+		var _, err = fs.EncodeCursor(url.Values{}, map[string]any{
+			"lastName": "smith",
+		}, LANG_EN)
+
+		if err == nil {
+			t.Error("should throw error")
+		}
+	}
+
+	t.Run("test_missing_row_value", test_missing_row_value)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_value_types = func(t *testing.T) {
+		var fs = filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect:  "SELECT * FROM users",
+				Paginate:   true,
+				OrderBy:    []string{"active"},
+				Cursor:     true,
+				TieBreaker: "id",
+			},
+		)
+
+		var v = url.Values{
+			"$order_by": []string{"active"},
+			"$limit":    []string{"10"},
+		}
+
+		var cursor, err = fs.EncodeCursor(v, map[string]any{
+			"active": true,
+			"id":     42,
+		}, LANG_EN)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		v.Set("$cursor", cursor)
+
+		var sel string
+		if sel, err = fs.ValidateAndConstruct(v, LANG_EN); err != nil {
+			t.Error(err)
+			return
+		}
+
+		if sel != "SELECT * FROM users WHERE (active,id)>(TRUE,42) ORDER BY active ASC, id ASC LIMIT 10" {
+			t.Error("invalid sel:", sel)
+		}
+
+		// rejected when encoding (not in the next request)
+		_, err = fs.EncodeCursor(v, map[string]any{
+			"active": []int{1},
+			"id":     42,
+		}, LANG_EN)
+
+		if err == nil {
+			t.Error("should throw error")
+		}
+	}
+
+	t.Run("test_value_types", test_value_types)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_typed_values = func(t *testing.T) {
+		var new_filters = func(dialect filter.Dialect) interface {
+			ValidateAndConstruct(url.Values, string) (string, error)
+			ValidateAndConstructWithArgs(url.Values, string) (string, []any, error)
+			EncodeCursor(url.Values, map[string]any, string) (string, error)
+		} {
+			return filter.NewFilters(
+				filter.FilterConfigs{
+					SqlSelect:  "SELECT * FROM events",
+					Paginate:   true,
+					OrderBy:    []string{"created", "price"},
+					Cursor:     true,
+					TieBreaker: "id",
+					Dialect:    dialect,
+				},
+			)
+		}
+
+		var v = url.Values{
+			"$order_by": []string{"-created"},
+			"$limit":    []string{"10"},
+		}
+
+		var created = time.Date(2024, 4, 29, 16, 0, 0, 0, time.FixedZone("AST", 3*60*60))
+
+		var cases = []struct {
+			dialect filter.Dialect
+			where   string
+		}{
+			{filter.Postgres, "(created,id)<('2024-04-29 16:00:00+03:00',5)"},
+			{filter.MySQL, "(created,id)<('2024-04-29 16:00:00',5)"},
+			{filter.SQLite, "(created,id)<('2024-04-29 16:00:00',5)"},
+			{filter.SQLServer, "(created<'2024-04-29T16:00:00' OR (created='2024-04-29T16:00:00' AND id<5))"},
+		}
+
+		for _, c := range cases {
+			var fs = new_filters(c.dialect)
+
+			var cursor, err = fs.EncodeCursor(v, map[string]any{
+				"created": created,
+				"id":      5,
+			}, LANG_EN)
+
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+
+			var w = url.Values{
+				"$order_by": v["$order_by"],
+				"$limit":    v["$limit"],
+				"$cursor":   []string{cursor},
+			}
+
+			var sel string
+			if sel, err = fs.ValidateAndConstruct(w, LANG_EN); err != nil {
+				t.Error(err)
+				continue
+			}
+
+			if !strings.HasPrefix(sel, "SELECT * FROM events WHERE "+c.where+" ORDER BY ") {
+				t.Error("invalid sel:", sel)
+			}
+
+			// the driver gets a time.Time
+			var args []any
+			if _, args, err = fs.ValidateAndConstructWithArgs(w, LANG_EN); err != nil {
+				t.Error(err)
+				continue
+			}
+
+			if tm, ok := args[0].(time.Time); !ok || !tm.Equal(created) {
+				t.Error("invalid args:", args)
+			}
+		}
+
+		// exact decimals
+		var fs = new_filters(filter.Postgres)
+
+		v = url.Values{
+			"$order_by": []string{"price"},
+			"$limit":    []string{"10"},
+		}
+
+		var cursor, err = fs.EncodeCursor(v, map[string]any{
+			"price": json.Number("12345678901234567.89"),
+			"id":    int64(9223372036854775807),
+		}, LANG_EN)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		v.Set("$cursor", cursor)
+
+		var sel string
+		if sel, err = fs.ValidateAndConstruct(v, LANG_EN); err != nil {
+			t.Error(err)
+			return
+		}
+
+		if sel != "SELECT * FROM events WHERE (price,id)>(12345678901234567.89,9223372036854775807) ORDER BY price ASC, id ASC LIMIT 10" {
+			t.Error("invalid sel:", sel)
+		}
+	}
+
+	t.Run("test_typed_values", test_typed_values)
+}
@@ -8,18 +8,28 @@ import (
 
 const DATE_LAYOUT = "2006-01-02"
 
+type Calendar int
+
+const (
+	GregorianCalendar Calendar = iota
+	HijriCalendar              // Umm al-Qura (1365-1500 AH)
+)
+
 type date_filter struct {
 	key          string
 	col_alias    string
 	after_now    bool
 	check_after  bool
 	after_unix   int64
-	after_date   string
 	before_now   bool
 	check_before bool
 	before_unix  int64
-	before_date  string
 	null_opt     bool
+	timestamp    bool
+	loc          *time.Location
+	clock        Clock
+	week_start   time.Weekday
+	calendar     Calendar
 }
 
 type DateFilterOpts struct {
@@ -30,6 +40,14 @@ type DateFilterOpts struct {
 	BeforeNow bool
 	Before    string // should be in (YYYY-MM-DD) format
 	NullOpt   bool
+	Timestamp bool           // the column is a timestamp, the dates are compared as day ranges
+	Location  *time.Location // zone of the day boundaries of a timestamp column and of the relative dates (default: UTC)
+	Clock     Clock          // the relative dates, the presets and (AfterNow, BeforeNow) are evaluated against it (default: the Clock of FilterConfigs)
+	WeekStart time.Weekday   // first day of the week of (startOfWeek) and (this_week) (default: Sunday)
+	// calendar of the inputs without a marker (default: GregorianCalendar),
+	// the marker (h or هـ) after a date makes it Hijri and (g or م) makes it Gregorian: 1445-10-20h,
+	// the errors report the dates in the calendar of the input
+	Calendar Calendar
 }
 
 func MustCreateNewDateFilter(opts DateFilterOpts) *date_filter {
@@ -47,12 +65,21 @@ func NewDateFilter(opts DateFilterOpts) (*date_filter, error) {
 		opts.ColAlias = opts.Key
 	}
 
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+
 	var d_filter = date_filter{
 		key:        opts.Key,
 		col_alias:  opts.ColAlias,
 		after_now:  opts.AfterNow,
 		before_now: opts.BeforeNow,
 		null_opt:   opts.NullOpt,
+		timestamp:  opts.Timestamp,
+		loc:        opts.Location,
+		clock:      opts.Clock,
+		week_start: opts.WeekStart,
+		calendar:   opts.Calendar,
 	}
 
 	var err error
@@ -63,7 +90,6 @@ func NewDateFilter(opts DateFilterOpts) (*date_filter, error) {
 			return nil, err
 		}
 		d_filter.after_unix = t_after.Unix()
-		d_filter.after_date = t_after.Format(DATE_LAYOUT)
 		d_filter.check_after = true
 	}
 
@@ -75,168 +101,248 @@ func NewDateFilter(opts DateFilterOpts) (*date_filter, error) {
 		}
 
 		d_filter.before_unix = t_before.Unix()
-		d_filter.before_date = t_before.Format(DATE_LAYOUT)
 		d_filter.check_before = true
 	}
 
 	return &d_filter, nil
 }
 
-func (d *date_filter) validate_and_construct(
+func (d *date_filter) ValidateAndConstruct(
 	v url.Values,
 	lang string,
+	ctx *SqlCtx,
 ) (string, error) {
-	// pr, pre, ps, pse, eq, null
+	// preset, eq, pr, pre, ps, pse, null
+
+	var construct = func(op string, date string) string {
+		if d.timestamp {
+			return d.construct_day_range(op, date, ctx)
+		}
+		return ctx.Ident(d.col_alias) + date_ops[op] + ctx.Bind(date)
+	}
+
+	var today = d.today(ctx)
 
-	var input string
-	var ok bool
-	var err error
 	var cond string
+	var err error
 
-	if input, ok = get_first_el_if_exists(v, d.key+"[eq]"); ok {
-		if input, err = d.validate_val(input, "eq", lang); err != nil {
-			return "", err
-		}
-		cond = d.col_alias + "=" + wrap_with_single_quote(input)
+	if input, ok := get_first_el_if_exists(v, d.key+"[preset]"); ok {
+		cond, err = d.construct_preset(input, today, lang, construct)
 	} else {
-		var conds = []string{}
-		if input, ok = get_first_el_if_exists(v, d.key+"[pr]"); ok {
+		cond, err = construct_date_range(v, d.key, func(op string, input string) (string, error) {
+			var date, err = d.validate_val(input, op, today, lang)
 
-			if input, err = d.validate_val(input, "pr", lang); err != nil {
+			if err != nil {
 				return "", err
 			}
-			conds = append(conds, d.col_alias+"<"+wrap_with_single_quote(input))
 
-		} else if input, ok = get_first_el_if_exists(v, d.key+"[pre]"); ok {
+			return construct(op, date), nil
+		})
+	}
 
-			if input, err = d.validate_val(input, "pre", lang); err != nil {
-				return "", err
-			}
-			conds = append(conds, d.col_alias+"<="+wrap_with_single_quote(input))
-		}
+	if err != nil {
+		return "", err
+	}
 
-		if input, ok = get_first_el_if_exists(v, d.key+"[ps]"); ok {
+	if d.null_opt {
+		cond = with_null_cond(v, d.key, ctx.Ident(d.col_alias), cond)
+	}
 
-			if input, err = d.validate_val(input, "ps", lang); err != nil {
-				return "", err
-			}
-			conds = append(conds, d.col_alias+">"+wrap_with_single_quote(input))
+	return cond, nil
+}
 
-		} else if input, ok = get_first_el_if_exists(v, d.key+"[pse]"); ok {
+// construct_preset constructs the range of a named preset (last_7_days => pse=today-6 AND pre=today)
+func (d *date_filter) construct_preset(
+	input string,
+	today time.Time,
+	lang string,
+	construct func(op string, date string) string,
+) (string, error) {
 
-			if input, err = d.validate_val(input, "pse", lang); err != nil {
-				return "", err
-			}
-			conds = append(conds, d.col_alias+">="+wrap_with_single_quote(input))
-		}
+	var preset, ok = date_presets[input]
 
-		switch len(conds) {
-		case 1:
-			cond = conds[0]
-		case 2:
-			cond = "(" + strings.Join(conds, " AND ") + ")"
-		}
+	if !ok {
+		return "", new_err(d.key, input, invalid_preset_err(), lang, "preset")
 	}
 
-	if d.null_opt {
-		if input, ok = get_first_el_if_exists(v, d.key+"[null]"); ok {
-			if cond != "" {
-				if input != "0" {
-					cond = "(" + cond + " OR " + d.col_alias + " IS NULL)"
-				}
-			} else {
-				if input == "0" {
-					cond = d.col_alias + " IS NOT NULL"
-				} else {
-					cond = d.col_alias + " IS NULL"
-				}
-			}
-		}
+	var start, end = preset(today, d.week_start)
+
+	var hijri = d.calendar == HijriCalendar
+
+	var s_start, err = d.check_bounds(input, utc_date(start), hijri, today, "preset", lang)
+
+	if err != nil {
+		return "", err
 	}
 
-	return cond, nil
+	var s_end string
+
+	if s_end, err = d.check_bounds(input, utc_date(end), hijri, today, "preset", lang); err != nil {
+		return "", err
+	}
+
+	if s_start == s_end {
+		return construct("eq", s_start), nil
+	}
+
+	return "(" + construct("pse", s_start) + " AND " + construct("pre", s_end) + ")", nil
+}
+
+// construct_day_range compares a timestamp column with the boundaries of the day:
+// eq => (col>=day AND col<day+1), pr => col<day, pre => col<day+1,
+// ps => col>=day+1, pse => col>=day
+func (d *date_filter) construct_day_range(op string, date string, ctx *SqlCtx) string {
+	// the date is already validated
+	var start, _ = time.ParseInLocation(DATE_LAYOUT, date, d.loc)
+	var end = start.AddDate(0, 0, 1)
+
+	var col = ctx.Ident(d.col_alias)
+
+	switch op {
+	case "eq":
+		return "(" + col + ">=" + ctx.Bind(start) + " AND " + col + "<" + ctx.Bind(end) + ")"
+	case "pr":
+		return col + "<" + ctx.Bind(start)
+	case "pre":
+		return col + "<" + ctx.Bind(end)
+	case "ps":
+		return col + ">=" + ctx.Bind(end)
+	}
+
+	return col + ">=" + ctx.Bind(start)
 }
 
-func (d *date_filter) validate_val(input string, op string, lang string) (string, error) {
+func (d *date_filter) validate_val(input string, op string, today time.Time, lang string) (string, error) {
 
-	var t, err = time.Parse(DATE_LAYOUT, input)
+	var t, hijri, ok = d.parse_date(input, today)
 
-	if err != nil {
-		return "", &FilterErr{
-			Key:     d.key,
-			Value:   input,
-			Path:    []any{op},
-			Message: invalid_date_err(lang),
-		}
+	if !ok {
+		return "", new_err(d.key, input, invalid_date_err(), lang, op)
+	}
+
+	return d.check_bounds(input, t, hijri, today, op, lang)
+}
+
+// parse_date parses a relative (today, -7d, ...) or an absolute date (YYYY-MM-DD in the calendar of the input),
+// the date is returned at midnight UTC, hijri reports whether the user entered it in the Hijri calendar
+func (d *date_filter) parse_date(input string, today time.Time) (t time.Time, hijri bool, ok bool) {
+	input = normalize_digits(input)
+
+	if t, ok = resolve_relative_date(input, today, d.week_start); ok {
+		return utc_date(t), d.calendar == HijriCalendar, true
+	}
+
+	var date, calendar = split_calendar_marker(input, d.calendar)
+
+	if calendar == HijriCalendar {
+		t, ok = parse_hijri(date)
+		return t, true, ok
+	}
+
+	var err error
+
+	if t, err = time.Parse(DATE_LAYOUT, date); err != nil {
+		return time.Time{}, false, false
 	}
 
-	input = t.Format(DATE_LAYOUT)
+	return t, false, true
+}
+
+// today returns the midnight of the current day in the configured zone
+func (d *date_filter) today(ctx *SqlCtx) time.Time {
+	if d.clock != nil {
+		return midnight_in(d.clock.Now(), d.loc)
+	}
+	return midnight_in(ctx.Now(), d.loc)
+}
+
+// check_bounds returns the date of t (YYYY-MM-DD) if it is in the allowed range
+// (AfterNow, BeforeNow) are compared by day, so today is neither before nor after now
+func (d *date_filter) check_bounds(
+	input string,
+	t time.Time,
+	hijri bool,
+	today time.Time,
+	op string,
+	lang string,
+) (string, error) {
+
+	var date = t.Format(DATE_LAYOUT)
+
+	// the date in the calendar of the user
+	var s_input = format_date(t, hijri, lang)
 
 	var input_unix = t.Unix()
 
 	if d.after_now || d.before_now {
-		var now_t = time.Now()
+		var s_today = format_date(utc_date(today), hijri, lang)
 
-		var now_unix = now_t.Unix()
+		var today_unix = utc_date(today).Unix()
 
-		if d.after_now && input_unix < now_unix {
-			return "", &FilterErr{
-				Key:     d.key,
-				Value:   input,
-				Path:    []any{op},
-				Message: early_date_err(now_t.Format(DATE_LAYOUT), input, lang),
-			}
+		if d.after_now && input_unix < today_unix {
+			return "", new_err(d.key, input, early_date_err(s_today, s_input), lang, op)
 		}
 
-		if d.before_now && input_unix > now_unix {
-			return "", &FilterErr{
-				Key:     d.key,
-				Value:   input,
-				Path:    []any{op},
-				Message: late_date_err(now_t.Format(DATE_LAYOUT), input, lang),
-			}
+		if d.before_now && input_unix > today_unix {
+			return "", new_err(d.key, input, late_date_err(s_today, s_input), lang, op)
 		}
 	}
 
 	if d.check_after && input_unix < d.after_unix {
-		return "", &FilterErr{
-			Key:     d.key,
-			Value:   input,
-			Path:    []any{op},
-			Message: early_date_err(d.after_date, input, lang),
-		}
+		return "", new_err(d.key, input, early_date_err(format_date(time.Unix(d.after_unix, 0).UTC(), hijri, lang), s_input), lang, op)
 	}
 
 	if d.check_before && input_unix > d.before_unix {
-		return "", &FilterErr{
-			Key:     d.key,
-			Value:   input,
-			Path:    []any{op},
-			Message: late_date_err(d.before_date, input, lang),
+		return "", new_err(d.key, input, late_date_err(format_date(time.Unix(d.before_unix, 0).UTC(), hijri, lang), s_input), lang, op)
+	}
+
+	return date, nil
+}
+
+// split_calendar_marker removes the calendar marker of the date: 1445-10-20h => (1445-10-20, HijriCalendar),
+// the dates without a marker are in the default calendar
+func split_calendar_marker(input string, def Calendar) (string, Calendar) {
+	for _, marker := range []string{"h", "H", "هـ", "ه"} {
+		if date, ok := strings.CutSuffix(input, marker); ok {
+			return strings.TrimSpace(date), HijriCalendar
 		}
 	}
 
-	return input, nil
+	for _, marker := range []string{"g", "G", "م"} {
+		if date, ok := strings.CutSuffix(input, marker); ok {
+			return strings.TrimSpace(date), GregorianCalendar
+		}
+	}
 
+	return input, def
 }
 
-func invalid_date_err(lang string) string {
-	if lang == "ar" {
-		return "التاريخ غير صالح"
+// format_date returns t (midnight UTC) as (YYYY-MM-DD) in the Gregorian or the Hijri calendar
+func format_date(t time.Time, hijri bool, lang string) string {
+	if hijri {
+		if year, month, day, ok := gregorian_to_hijri(t); ok {
+			return format_hijri(year, month, day) + hijri_marker(lang)
+		}
 	}
-	return "The date is invalid"
+	return t.Format(DATE_LAYOUT)
 }
 
-func early_date_err(after string, input string, lang string) string {
-	if lang == "ar" {
-		return "يجب أن يكون التاريخ بعد (" + after + "), التاريخ الذي أدخلته (" + input + ")"
-	}
-	return "The date should be after (" + after + "), the date you entered (" + input + ")"
+func hijri_marker(lang string) string {
+	return translate(lang, "date.hijri_marker", nil)
 }
 
-func late_date_err(before string, input string, lang string) string {
-	if lang == "ar" {
-		return "يجب أن يكون التاريخ قبل (" + before + "), التاريخ الذي أدخلته (" + input + ")"
-	}
-	return "The date should be before (" + before + "), the date you entered (" + input + ")"
+func invalid_date_err() message {
+	return message{code: "date.invalid"}
+}
+
+func early_date_err(after string, input string) message {
+	return message{code: "date.too_early", params: map[string]any{"after": after, "input": input}}
+}
+
+func late_date_err(before string, input string) message {
+	return message{code: "date.too_late", params: map[string]any{"before": before, "input": input}}
+}
+
+func invalid_preset_err() message {
+	return message{code: "date.invalid_preset", params: map[string]any{"presets": date_preset_names}}
 }
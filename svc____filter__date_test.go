@@ -27,6 +27,11 @@ func TestDateFilter(t *testing.T) {
 		),
 	)
 
+	// 2024-04-17 in UTC, 2024-04-18 (Thursday) in AST
+	var frozen_now = time.Date(2024, 4, 17, 22, 30, 0, 0, time.UTC)
+
+	var frozen_clock = filter.ClockFunc(func() time.Time { return frozen_now })
+
 	var test_eq = func(t *testing.T) {
 		var v = url.Values{
 			"modified[eq]": []string{"2024-04-29"},
@@ -474,6 +479,7 @@ func TestDateFilter(t *testing.T) {
 		var fs = filter.NewFilters(
 			filter.FilterConfigs{
 				SqlSelect: "SELET * FROM users",
+				Clock:     frozen_clock,
 			},
 			filter.MustCreateNewDateFilter(filter.DateFilterOpts{
 				Key:      "modified",
@@ -481,7 +487,7 @@ func TestDateFilter(t *testing.T) {
 			}),
 		)
 
-		var dateBeforeNow = time.Now().AddDate(-1, -1, -1).Format("2006-01-02")
+		var dateBeforeNow = frozen_now.AddDate(-1, -1, -1).Format("2006-01-02")
 
 		var v = url.Values{
 			"modified[pr]": []string{dateBeforeNow},
@@ -494,7 +500,7 @@ func TestDateFilter(t *testing.T) {
 			return
 		}
 
-		var now = time.Now().Format("2006-01-02")
+		var now = frozen_now.Format("2006-01-02")
 
 		checkErrFields(
 			t,
@@ -527,6 +533,7 @@ func TestDateFilter(t *testing.T) {
 		var fs = filter.NewFilters(
 			filter.FilterConfigs{
 				SqlSelect: "SELET * FROM users",
+				Clock:     frozen_clock,
 			},
 			filter.MustCreateNewDateFilter(filter.DateFilterOpts{
 				Key:       "modified",
@@ -534,7 +541,7 @@ func TestDateFilter(t *testing.T) {
 			}),
 		)
 
-		var dateAfterNow = time.Now().AddDate(1, 1, 1).Format("2006-01-02")
+		var dateAfterNow = frozen_now.AddDate(1, 1, 1).Format("2006-01-02")
 
 		var v = url.Values{
 			"modified[pr]": []string{dateAfterNow},
@@ -547,7 +554,7 @@ func TestDateFilter(t *testing.T) {
 			return
 		}
 
-		var now = time.Now().Format("2006-01-02")
+		var now = frozen_now.Format("2006-01-02")
 
 		checkErrFields(
 			t,
@@ -698,6 +705,7 @@ func TestDateFilter(t *testing.T) {
 		var fs = filter.NewFilters(
 			filter.FilterConfigs{
 				SqlSelect: "SELET * FROM users",
+				Clock:     frozen_clock,
 			},
 			filter.MustCreateNewDateFilter(filter.DateFilterOpts{
 				Key:      "modified",
@@ -705,7 +713,7 @@ func TestDateFilter(t *testing.T) {
 			}),
 		)
 
-		var dateBeforeNow = time.Now().AddDate(-1, -1, -1).Format("2006-01-02")
+		var dateBeforeNow = frozen_now.AddDate(-1, -1, -1).Format("2006-01-02")
 
 		var v = url.Values{
 			"modified[pr]": []string{dateBeforeNow},
@@ -718,7 +726,7 @@ func TestDateFilter(t *testing.T) {
 			return
 		}
 
-		var now = time.Now().Format("2006-01-02")
+		var now = frozen_now.Format("2006-01-02")
 
 		checkErrFields(
 			t,
@@ -751,6 +759,7 @@ func TestDateFilter(t *testing.T) {
 		var fs = filter.NewFilters(
 			filter.FilterConfigs{
 				SqlSelect: "SELET * FROM users",
+				Clock:     frozen_clock,
 			},
 			filter.MustCreateNewDateFilter(filter.DateFilterOpts{
 				Key:       "modified",
@@ -758,7 +767,7 @@ func TestDateFilter(t *testing.T) {
 			}),
 		)
 
-		var dateAfterNow = time.Now().AddDate(1, 1, 1).Format("2006-01-02")
+		var dateAfterNow = frozen_now.AddDate(1, 1, 1).Format("2006-01-02")
 
 		var v = url.Values{
 			"modified[pr]": []string{dateAfterNow},
@@ -771,7 +780,7 @@ func TestDateFilter(t *testing.T) {
 			return
 		}
 
-		var now = time.Now().Format("2006-01-02")
+		var now = frozen_now.Format("2006-01-02")
 
 		checkErrFields(
 			t,
@@ -799,4 +808,432 @@ func TestDateFilter(t *testing.T) {
 	//
 	//
 	//
+
+	var test_timestamp_col = func(t *testing.T) {
+		var fs = filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect: sql,
+			},
+			filter.MustCreateNewDateFilter(
+				filter.DateFilterOpts{
+					Key:       "modified",
+					Timestamp: true,
+					Location:  time.FixedZone("AST", 3*60*60),
+					NullOpt:   true,
+				},
+			),
+		)
+
+		var cases = []struct {
+			v     url.Values
+			query string
+		}{
+			{
+				url.Values{"modified[eq]": []string{"2024-04-29"}},
+				sql + " WHERE (modified>='2024-04-29 00:00:00+03:00' AND modified<'2024-04-30 00:00:00+03:00')",
+			},
+			{
+				url.Values{"modified[pr]": []string{"2024-04-29"}},
+				sql + " WHERE modified<'2024-04-29 00:00:00+03:00'",
+			},
+			{
+				url.Values{"modified[pre]": []string{"2024-04-30"}},
+				sql + " WHERE modified<'2024-05-01 00:00:00+03:00'",
+			},
+			{
+				url.Values{"modified[ps]": []string{"2024-04-29"}},
+				sql + " WHERE modified>='2024-04-30 00:00:00+03:00'",
+			},
+			{
+				url.Values{"modified[pse]": []string{"2024-12-31"}, "modified[null]": []string{"1"}},
+				sql + " WHERE (modified>='2024-12-31 00:00:00+03:00' OR modified IS NULL)",
+			},
+		}
+
+		for _, c := range cases {
+			var query, err = fs.ValidateAndConstruct(c.v, LANG_AR)
+
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+
+			if query != c.query {
+				t.Error("invalid query:", query)
+			}
+		}
+	}
+	t.Run("test_timestamp_col", test_timestamp_col)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var new_relative_filters = func(now time.Time, timestamp bool) interface {
+		ValidateAndConstruct(url.Values, string) (string, error)
+	} {
+		return filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect: sql,
+			},
+			filter.MustCreateNewDateFilter(
+				filter.DateFilterOpts{
+					Key:       "modified",
+					Timestamp: timestamp,
+					Location:  time.FixedZone("AST", 3*60*60),
+					Clock:     filter.ClockFunc(func() time.Time { return now }),
+					WeekStart: time.Saturday,
+				},
+			),
+		)
+	}
+
+	var test_relative = func(t *testing.T) {
+		var fs = new_relative_filters(frozen_now, false)
+
+		var inputs = map[string]string{
+			"now":          "2024-04-18",
+			"today":        "2024-04-18",
+			"yesterday":    "2024-04-17",
+			"-7d":          "2024-04-11",
+			"+2w":          "2024-05-02",
+			"+1m":          "2024-05-18",
+			"-1y":          "2023-04-18",
+			"startOfWeek":  "2024-04-13",
+			"endOfWeek":    "2024-04-19",
+			"startOfMonth": "2024-04-01",
+			"endOfMonth":   "2024-04-30",
+			"startOfYear":  "2024-01-01",
+			"endOfYear":    "2024-12-31",
+		}
+
+		for input, date := range inputs {
+			var v = url.Values{
+				"modified[pse]": []string{input},
+			}
+
+			var query, err = fs.ValidateAndConstruct(v, LANG_AR)
+
+			if err != nil {
+				t.Error(input, err)
+				continue
+			}
+
+			if query != sql+" WHERE modified>='"+date+"'" {
+				t.Error("invalid query:", input, query)
+			}
+		}
+
+		// the day is clamped to the end of the month
+		var query, err = new_relative_filters(time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC), false).
+			ValidateAndConstruct(url.Values{"modified[eq]": []string{"-1m"}}, LANG_AR)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != sql+" WHERE modified='2024-02-29'" {
+			t.Error("invalid query:", query)
+		}
+
+		_, err = fs.ValidateAndConstruct(url.Values{"modified[eq]": []string{"-7x"}}, LANG_EN)
+
+		if err == nil {
+			t.Error("should throw error")
+		}
+	}
+	t.Run("test_relative", test_relative)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_preset = func(t *testing.T) {
+		var fs = new_relative_filters(frozen_now, false)
+
+		var presets = map[string]string{
+			"today":        sql + " WHERE modified='2024-04-18'",
+			"yesterday":    sql + " WHERE modified='2024-04-17'",
+			"this_week":    sql + " WHERE (modified>='2024-04-13' AND modified<='2024-04-19')",
+			"last_7_days":  sql + " WHERE (modified>='2024-04-12' AND modified<='2024-04-18')",
+			"last_30_days": sql + " WHERE (modified>='2024-03-20' AND modified<='2024-04-18')",
+			"this_month":   sql + " WHERE (modified>='2024-04-01' AND modified<='2024-04-30')",
+			"this_year":    sql + " WHERE (modified>='2024-01-01' AND modified<='2024-12-31')",
+		}
+
+		for preset, expected := range presets {
+			var v = url.Values{
+				"modified[preset]": []string{preset},
+			}
+
+			var query, err = fs.ValidateAndConstruct(v, LANG_AR)
+
+			if err != nil {
+				t.Error(preset, err)
+				continue
+			}
+
+			if query != expected {
+				t.Error("invalid query:", preset, query)
+			}
+		}
+
+		var query, err = new_relative_filters(frozen_now, true).
+			ValidateAndConstruct(url.Values{"modified[preset]": []string{"yesterday"}}, LANG_AR)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != sql+" WHERE (modified>='2024-04-17 00:00:00+03:00' AND modified<'2024-04-18 00:00:00+03:00')" {
+			t.Error("invalid query:", query)
+		}
+
+		_, err = fs.ValidateAndConstruct(url.Values{"modified[preset]": []string{"last_year"}}, LANG_EN)
+
+		if err == nil {
+			t.Error("should throw error")
+			return
+		}
+
+		var f_err = (*err.(*filter.FilterErrs))[0].(*filter.FilterErr)
+
+		if f_err.Value != "last_year" || f_err.Path[0] != "preset" ||
+			f_err.Message != "The preset should be one of (today, yesterday, this_week, last_7_days, last_30_days, this_month, this_year)" {
+			t.Error("invalid error:", f_err)
+		}
+	}
+	t.Run("test_preset", test_preset)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_now_by_day = func(t *testing.T) {
+		var new_fs = func(opts filter.DateFilterOpts) interface {
+			ValidateAndConstruct(url.Values, string) (string, error)
+		} {
+			opts.Key = "modified"
+			return filter.NewFilters(
+				filter.FilterConfigs{
+					SqlSelect: sql,
+					Clock:     frozen_clock,
+				},
+				filter.MustCreateNewDateFilter(opts),
+			)
+		}
+
+		var cases = []struct {
+			opts  filter.DateFilterOpts
+			input string
+			valid bool
+		}{
+			// today is neither before nor after now
+			{filter.DateFilterOpts{AfterNow: true}, "2024-04-17", true},
+			{filter.DateFilterOpts{BeforeNow: true}, "2024-04-17", true},
+			{filter.DateFilterOpts{AfterNow: true}, "today", true},
+			{filter.DateFilterOpts{AfterNow: true}, "2024-04-16", false},
+			{filter.DateFilterOpts{BeforeNow: true}, "2024-04-18", false},
+			// the day of now in the configured zone
+			{filter.DateFilterOpts{AfterNow: true, Location: time.FixedZone("AST", 3*60*60)}, "2024-04-17", false},
+			{filter.DateFilterOpts{BeforeNow: true, Location: time.FixedZone("AST", 3*60*60)}, "2024-04-18", true},
+			// the clock of the filter overrides the clock of the configs
+			{filter.DateFilterOpts{AfterNow: true, Clock: filter.ClockFunc(func() time.Time { return frozen_now.AddDate(0, 0, -1) })}, "2024-04-16", true},
+		}
+
+		for _, c := range cases {
+			var v = url.Values{
+				"modified[eq]": []string{c.input},
+			}
+
+			var _, err = new_fs(c.opts).ValidateAndConstruct(v, LANG_EN)
+
+			if c.valid && err != nil {
+				t.Error(c.input, err)
+			}
+
+			if !c.valid && err == nil {
+				t.Error("should throw error:", c.input)
+			}
+		}
+	}
+	t.Run("test_now_by_day", test_now_by_day)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_hijri = func(t *testing.T) {
+		var hijri_fs = filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect: sql,
+			},
+			filter.MustCreateNewDateFilter(
+				filter.DateFilterOpts{
+					Key:      "modified",
+					Calendar: filter.HijriCalendar,
+				},
+			),
+		)
+
+		var cases = []struct {
+			fs interface {
+				ValidateAndConstruct(url.Values, string) (string, error)
+			}
+			input string
+		}{
+			{fs, "1445-10-20h"},
+			{fs, "1445/10/20 هـ"},
+			{fs, "2024-04-29"},
+			{hijri_fs, "1445-10-20"},
+			{hijri_fs, "1445-10-20هـ"},
+			{hijri_fs, "2024-04-29g"},
+			{hijri_fs, "2024-04-29م"},
+		}
+
+		for _, c := range cases {
+			var v = url.Values{
+				"modified[eq]": []string{c.input},
+			}
+
+			var query, err = c.fs.ValidateAndConstruct(v, LANG_AR)
+
+			if err != nil {
+				t.Error(c.input, err)
+				continue
+			}
+
+			if query != sql+" WHERE modified='2024-04-29'" {
+				t.Error("invalid query:", c.input, query)
+			}
+		}
+
+		// Shawwal 1445 has 29 days
+		for _, input := range []string{"1445-10-30", "1445-13-01", "1300-01-01", "2024-04-29"} {
+			var v = url.Values{
+				"modified[eq]": []string{input},
+			}
+
+			var _, err = hijri_fs.ValidateAndConstruct(v, LANG_EN)
+
+			if err == nil {
+				t.Error("should throw error:", input)
+			}
+		}
+	}
+	t.Run("test_hijri", test_hijri)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_hijri_bounds = func(t *testing.T) {
+		var cases = []struct {
+			input string
+			lang  string
+			msg   string
+		}{
+			{"1445-11-01h", LANG_EN, "The date should be before (1445-10-22 AH), the date you entered (1445-11-01 AH)"},
+			{"1445-11-01h", LANG_AR, "يجب أن يكون التاريخ قبل (1445-10-22هـ), التاريخ الذي أدخلته (1445-11-01هـ)"},
+			{"1441-01-01h", LANG_EN, "The date should be after (1441-05-07 AH), the date you entered (1441-01-01 AH)"},
+			// the Gregorian inputs are reported in Gregorian
+			{"2024-05-09", LANG_EN, "The date should be before (2024-05-01), the date you entered (2024-05-09)"},
+		}
+
+		for _, c := range cases {
+			var v = url.Values{
+				"modified[eq]": []string{c.input},
+			}
+
+			var _, err = fs.ValidateAndConstruct(v, c.lang)
+
+			if err == nil {
+				t.Error("should throw error:", c.input)
+				continue
+			}
+
+			checkErrFields(
+				t,
+				(*err.(*filter.FilterErrs))[0].(*filter.FilterErr),
+				"modified",
+				c.input,
+				c.msg,
+				"eq",
+			)
+		}
+	}
+	t.Run("test_hijri_bounds", test_hijri_bounds)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_arabic_digits = func(t *testing.T) {
+		var fs = filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect:    sql,
+				ArabicDigits: true,
+			},
+			filter.MustCreateNewDateFilter(
+				filter.DateFilterOpts{
+					Key:    "modified",
+					Before: "2024-05-01",
+				},
+			),
+		)
+
+		for _, input := range []string{"٢٠٢٤-٠٤-٢٩", "۲۰۲۴-۰۴-۲۹", "١٤٤٥-١٠-٢٠هـ"} {
+			var v = url.Values{
+				"modified[eq]": []string{input},
+			}
+
+			var query, err = fs.ValidateAndConstruct(v, LANG_AR)
+
+			if err != nil {
+				t.Error(input, err)
+				continue
+			}
+
+			if query != sql+" WHERE modified='2024-04-29'" {
+				t.Error("invalid query:", query)
+			}
+		}
+
+		var _, err = fs.ValidateAndConstruct(url.Values{"modified[eq]": []string{"٢٠٢٤-٠٥-٠٩"}}, LANG_AR)
+
+		if err == nil {
+			t.Error("should throw error")
+			return
+		}
+
+		checkErrFields(
+			t,
+			(*err.(*filter.FilterErrs))[0].(*filter.FilterErr),
+			"modified",
+			"٢٠٢٤-٠٥-٠٩",
+			"يجب أن يكون التاريخ قبل (٢٠٢٤-٠٥-٠١), التاريخ الذي أدخلته (٢٠٢٤-٠٥-٠٩)",
+			"eq",
+		)
+	}
+	t.Run("test_arabic_digits", test_arabic_digits)
 }
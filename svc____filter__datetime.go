@@ -0,0 +1,167 @@
+package filter
+
+import (
+	"net/url"
+	"time"
+)
+
+type datetime_filter struct {
+	key          string
+	col_alias    string
+	layouts      []string
+	loc          *time.Location
+	after_now    bool
+	check_after  bool
+	after        time.Time
+	before_now   bool
+	check_before bool
+	before       time.Time
+	null_opt     bool
+	clock        Clock
+}
+
+type DateTimeFilterOpts struct {
+	Key       string
+	ColAlias  string
+	Layouts   []string       // accepted layouts (default: time.RFC3339)
+	Location  *time.Location // zone of the inputs without offset and of the timestamps in the query (default: UTC)
+	AfterNow  bool
+	After     string // should be in RFC 3339 format
+	BeforeNow bool
+	Before    string // should be in RFC 3339 format
+	NullOpt   bool
+	Clock     Clock // (AfterNow, BeforeNow) are evaluated against it (default: the Clock of FilterConfigs)
+}
+
+func MustCreateNewDateTimeFilter(opts DateTimeFilterOpts) *datetime_filter {
+	var ft, err = NewDateTimeFilter(opts)
+
+	if err != nil {
+		panic(err)
+	}
+	return ft
+}
+
+func NewDateTimeFilter(opts DateTimeFilterOpts) (*datetime_filter, error) {
+	if opts.ColAlias == "" {
+		opts.ColAlias = opts.Key
+	}
+
+	if len(opts.Layouts) == 0 {
+		opts.Layouts = []string{time.RFC3339}
+	}
+
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+
+	var dt_filter = datetime_filter{
+		key:        opts.Key,
+		col_alias:  opts.ColAlias,
+		layouts:    opts.Layouts,
+		loc:        opts.Location,
+		after_now:  opts.AfterNow,
+		before_now: opts.BeforeNow,
+		null_opt:   opts.NullOpt,
+		clock:      opts.Clock,
+	}
+
+	var err error
+
+	if !opts.AfterNow && opts.After != "" {
+		if dt_filter.after, err = time.Parse(time.RFC3339, opts.After); err != nil {
+			return nil, err
+		}
+		dt_filter.after = dt_filter.after.In(opts.Location)
+		dt_filter.check_after = true
+	}
+
+	if !opts.BeforeNow && opts.Before != "" {
+		if dt_filter.before, err = time.Parse(time.RFC3339, opts.Before); err != nil {
+			return nil, err
+		}
+		dt_filter.before = dt_filter.before.In(opts.Location)
+		dt_filter.check_before = true
+	}
+
+	return &dt_filter, nil
+}
+
+func (d *datetime_filter) ValidateAndConstruct(
+	v url.Values,
+	lang string,
+	ctx *SqlCtx,
+) (string, error) {
+	// pr, pre, ps, pse, eq, null
+
+	var cond, err = construct_date_range(v, d.key, func(op string, input string) (string, error) {
+		var t, err = d.validate_val(input, op, ctx, lang)
+
+		if err != nil {
+			return "", err
+		}
+
+		return ctx.Ident(d.col_alias) + date_ops[op] + ctx.Bind(t), nil
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	if d.null_opt {
+		cond = with_null_cond(v, d.key, ctx.Ident(d.col_alias), cond)
+	}
+
+	return cond, nil
+}
+
+// validate_val returns the input in the configured zone
+func (d *datetime_filter) validate_val(input string, op string, ctx *SqlCtx, lang string) (time.Time, error) {
+	var t time.Time
+	var err error
+
+	var s_val = normalize_digits(input)
+
+	for _, layout := range d.layouts {
+		// the inputs without offset are in the configured zone
+		if t, err = time.ParseInLocation(layout, s_val, d.loc); err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		return time.Time{}, new_err(d.key, input, invalid_date_err(), lang, op)
+	}
+
+	t = t.In(d.loc)
+
+	var s_input = t.Format(time.RFC3339)
+
+	if d.after_now || d.before_now {
+		var now = ctx.Now()
+
+		if d.clock != nil {
+			now = d.clock.Now()
+		}
+
+		now = now.In(d.loc)
+
+		if d.after_now && t.Before(now) {
+			return time.Time{}, new_err(d.key, input, early_date_err(now.Format(time.RFC3339), s_input), lang, op)
+		}
+
+		if d.before_now && t.After(now) {
+			return time.Time{}, new_err(d.key, input, late_date_err(now.Format(time.RFC3339), s_input), lang, op)
+		}
+	}
+
+	if d.check_after && t.Before(d.after) {
+		return time.Time{}, new_err(d.key, input, early_date_err(d.after.Format(time.RFC3339), s_input), lang, op)
+	}
+
+	if d.check_before && t.After(d.before) {
+		return time.Time{}, new_err(d.key, input, late_date_err(d.before.Format(time.RFC3339), s_input), lang, op)
+	}
+
+	return t, nil
+}
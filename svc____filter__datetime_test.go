@@ -0,0 +1,269 @@
+package filter_test
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/MaSTeR2W/filter"
+)
+
+func TestDateTimeFilter(t *testing.T) {
+	const (
+		LANG_AR = "ar"
+		LANG_EN = "en"
+	)
+
+	var sql = "SELECT * FROM events"
+
+	var riyadh = time.FixedZone("AST", 3*60*60)
+
+	var new_filters = func(dialect filter.Dialect, loc *time.Location) interface {
+		ValidateAndConstruct(url.Values, string) (string, error)
+		ValidateAndConstructWithArgs(url.Values, string) (string, []any, error)
+	} {
+		return filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect: sql,
+				Dialect:   dialect,
+			},
+			filter.MustCreateNewDateTimeFilter(filter.DateTimeFilterOpts{
+				Key:      "starts",
+				ColAlias: "starts_at",
+				Layouts:  []string{time.RFC3339, "2006-01-02T15:04"},
+				Location: loc,
+				After:    "2020-01-01T00:00:00Z",
+				Before:   "2030-01-01T00:00:00Z",
+				NullOpt:  true,
+			}),
+		)
+	}
+
+	var fs = new_filters(nil, nil)
+
+	var test_normalize = func(t *testing.T) {
+		var v = url.Values{
+			"starts[ps]": []string{"2024-04-29T13:00:00+03:00"},
+		}
+
+		var query, err = fs.ValidateAndConstruct(v, LANG_AR)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != sql+" WHERE starts_at>'2024-04-29 10:00:00+00:00'" {
+			t.Error("invalid query:", query)
+		}
+
+		// without offset => the configured zone
+		v = url.Values{
+			"starts[pre]": []string{"2024-04-29T13:00"},
+		}
+
+		query, err = new_filters(nil, riyadh).ValidateAndConstruct(v, LANG_AR)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != sql+" WHERE starts_at<='2024-04-29 13:00:00+03:00'" {
+			t.Error("invalid query:", query)
+		}
+	}
+
+	t.Run("test_normalize", test_normalize)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_dialects = func(t *testing.T) {
+		var v = url.Values{
+			"starts[eq]": []string{"2024-04-29T13:00:00.5+03:00"},
+		}
+
+		var cases = []struct {
+			dialect filter.Dialect
+			query   string
+		}{
+			{filter.Postgres, sql + " WHERE starts_at='2024-04-29 10:00:00.5+00:00'"},
+			{filter.MySQL, sql + " WHERE starts_at='2024-04-29 10:00:00.5'"},
+			{filter.SQLite, sql + " WHERE starts_at='2024-04-29 10:00:00.5'"},
+			{filter.SQLServer, sql + " WHERE starts_at='2024-04-29T10:00:00.5'"},
+		}
+
+		for _, c := range cases {
+			var query, err = new_filters(c.dialect, nil).ValidateAndConstruct(v, LANG_AR)
+
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+
+			if query != c.query {
+				t.Error("invalid query:", query)
+			}
+		}
+	}
+
+	t.Run("test_dialects", test_dialects)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_args = func(t *testing.T) {
+		var v = url.Values{
+			"starts[pse]":  []string{"2024-04-29T13:00:00+03:00"},
+			"starts[pr]":   []string{"2024-05-01T00:00:00Z"},
+			"starts[null]": []string{"1"},
+		}
+
+		var query, args, err = new_filters(filter.Postgres, riyadh).ValidateAndConstructWithArgs(v, LANG_AR)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != sql+" WHERE ((starts_at<$1 AND starts_at>=$2) OR starts_at IS NULL)" {
+			t.Error("invalid query:", query)
+		}
+
+		var expected = []any{
+			time.Date(2024, 5, 1, 3, 0, 0, 0, riyadh),
+			time.Date(2024, 4, 29, 13, 0, 0, 0, riyadh),
+		}
+
+		if len(args) != len(expected) {
+			t.Error("invalid args:", args)
+			return
+		}
+
+		for i, arg := range args {
+			var tm, ok = arg.(time.Time)
+
+			if !ok || !tm.Equal(expected[i].(time.Time)) || tm.Location() != riyadh {
+				t.Error("invalid arg:", arg)
+			}
+		}
+	}
+
+	t.Run("test_args", test_args)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_errors = func(t *testing.T) {
+		var cases = []struct {
+			op    string
+			input string
+			lang  string
+			msg   string
+		}{
+			{"eq", "2024-04-29", LANG_EN, "The date is invalid"},
+			{"ps", "2024-04-29T25:00:00Z", LANG_AR, "التاريخ غير صالح"},
+			{"pr", "2019-12-31T23:59:59Z", LANG_EN, "The date should be after (2020-01-01T00:00:00Z), the date you entered (2019-12-31T23:59:59Z)"},
+			{"pse", "2030-01-01T03:00:01+03:00", LANG_EN, "The date should be before (2030-01-01T00:00:00Z), the date you entered (2030-01-01T00:00:01Z)"},
+		}
+
+		for _, c := range cases {
+			var v = url.Values{
+				"starts[" + c.op + "]": []string{c.input},
+			}
+
+			var _, err = fs.ValidateAndConstruct(v, c.lang)
+
+			if err == nil {
+				t.Error("should throw error:", c.input)
+				continue
+			}
+
+			var f_err = (*err.(*filter.FilterErrs))[0].(*filter.FilterErr)
+
+			if f_err.Key != "starts" || f_err.Value != c.input || !reflect.DeepEqual(f_err.Path, []any{c.op}) || f_err.Message != c.msg {
+				t.Error("invalid error:", c.input, f_err)
+			}
+		}
+	}
+
+	t.Run("test_errors", test_errors)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_invalid_opts = func(t *testing.T) {
+		var _, err = filter.NewDateTimeFilter(filter.DateTimeFilterOpts{
+			Key:   "starts",
+			After: "2020-01-01",
+		})
+
+		if err == nil {
+			t.Error("should throw error")
+		}
+	}
+
+	t.Run("test_invalid_opts", test_invalid_opts)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_after_now = func(t *testing.T) {
+		var fs = filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect: sql,
+				Clock: filter.ClockFunc(func() time.Time {
+					return time.Date(2024, 4, 29, 10, 0, 0, 0, time.UTC)
+				}),
+			},
+			filter.MustCreateNewDateTimeFilter(filter.DateTimeFilterOpts{
+				Key:      "starts",
+				Location: riyadh,
+				AfterNow: true,
+			}),
+		)
+
+		var _, err = fs.ValidateAndConstruct(url.Values{"starts[ps]": []string{"2024-04-29T13:30:00+03:00"}}, LANG_EN)
+
+		if err != nil {
+			t.Error(err)
+		}
+
+		_, err = fs.ValidateAndConstruct(url.Values{"starts[ps]": []string{"2024-04-29T12:30:00+03:00"}}, LANG_EN)
+
+		if err == nil {
+			t.Error("should throw error")
+			return
+		}
+
+		var f_err = (*err.(*filter.FilterErrs))[0].(*filter.FilterErr)
+
+		if f_err.Message != "The date should be after (2024-04-29T13:00:00+03:00), the date you entered (2024-04-29T12:30:00+03:00)" {
+			t.Error("invalid error:", f_err)
+		}
+	}
+
+	t.Run("test_after_now", test_after_now)
+}
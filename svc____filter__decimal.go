@@ -0,0 +1,271 @@
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// eq, gt, gte, lt, lte
+
+// no NaN, Inf, hex or underscores, only plain decimals with an optional exponent
+var decimal_regexp = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE]([+-]?\d+))?$`)
+
+// bigger exponents are not valid even for float64, and they are expensive for big.Rat
+const max_decimal_exp = 308
+
+type decimal_filter struct {
+	key         string
+	col_alias   string
+	max         *big.Rat
+	min         *big.Rat
+	check_max   bool
+	check_min   bool
+	s_max       string
+	s_min       string
+	check_prec  bool
+	int_digits  int
+	scale       int
+	bind_floats bool
+}
+
+type DecimalFilterOpts struct {
+	Key             string
+	ColAlias        string
+	EnableMax       bool
+	Max             float64
+	MaxDecimal      string // exact (Max) as a decimal string: "99999999999999999.99" (it replaces Max)
+	EnableMin       bool
+	Min             float64
+	MinDecimal      string // exact (Min) as a decimal string (it replaces Min)
+	EnablePrecision bool
+	Precision       int  // maximum number of digits, like NUMERIC(Precision, Scale)
+	Scale           int  // maximum number of digits after the decimal point
+	Float           bool // bind float64 arguments (for float columns) instead of decimal strings
+}
+
+func MustCreateNewDecimalFilter(opts DecimalFilterOpts) *decimal_filter {
+	var ft, err = NewDecimalFilter(opts)
+
+	if err != nil {
+		panic(err)
+	}
+	return ft
+}
+
+func NewDecimalFilter(opts DecimalFilterOpts) (*decimal_filter, error) {
+	if opts.ColAlias == "" {
+		opts.ColAlias = opts.Key
+	}
+
+	var f = decimal_filter{
+		key:         opts.Key,
+		col_alias:   opts.ColAlias,
+		bind_floats: opts.Float,
+	}
+
+	var err error
+
+	if opts.EnableMax {
+		f.check_max = true
+		if f.max, f.s_max, err = decimal_bound(opts.Max, opts.MaxDecimal); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.EnableMin {
+		f.check_min = true
+		if f.min, f.s_min, err = decimal_bound(opts.Min, opts.MinDecimal); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.EnablePrecision {
+		if opts.Scale < 0 || opts.Precision < opts.Scale {
+			return nil, errors.New("filter: the precision (" + strconv.Itoa(opts.Precision) +
+				") should not be less than the scale (" + strconv.Itoa(opts.Scale) + ")")
+		}
+
+		f.check_prec = true
+		f.int_digits = opts.Precision - opts.Scale
+		f.scale = opts.Scale
+	}
+
+	return &f, nil
+}
+
+func (d *decimal_filter) ValidateAndConstruct(
+	v url.Values,
+	lang string,
+	ctx *SqlCtx,
+) (string, error) {
+
+	var val string
+	var num raw_literal
+	var ok bool
+	var err error
+
+	if val, ok = get_first_el_if_exists(v, d.key+"[eq]"); ok {
+		if num, err = d.validate_val(val, "eq", lang); err != nil {
+			return "", err
+		}
+
+		return ctx.Ident(d.col_alias) + "=" + ctx.Bind(num), nil
+	}
+
+	var conds = []string{}
+
+	if val, ok = get_first_el_if_exists(v, d.key+"[gt]"); ok {
+		if num, err = d.validate_val(val, "gt", lang); err != nil {
+			return "", err
+		}
+		conds = append(conds, ctx.Ident(d.col_alias)+">"+ctx.Bind(num))
+	} else if val, ok = get_first_el_if_exists(v, d.key+"[gte]"); ok {
+		if num, err = d.validate_val(val, "gte", lang); err != nil {
+			return "", err
+		}
+		conds = append(conds, ctx.Ident(d.col_alias)+">="+ctx.Bind(num))
+	}
+
+	if val, ok = get_first_el_if_exists(v, d.key+"[lt]"); ok {
+		if num, err = d.validate_val(val, "lt", lang); err != nil {
+			return "", err
+		}
+		conds = append(conds, ctx.Ident(d.col_alias)+"<"+ctx.Bind(num))
+	} else if val, ok = get_first_el_if_exists(v, d.key+"[lte]"); ok {
+		if num, err = d.validate_val(val, "lte", lang); err != nil {
+			return "", err
+		}
+		conds = append(conds, ctx.Ident(d.col_alias)+"<="+ctx.Bind(num))
+	}
+
+	switch len(conds) {
+	case 0:
+		return "", nil
+	case 1:
+		return conds[0], nil
+	}
+
+	return "(" + conds[0] + " AND " + conds[1] + ")", nil
+}
+
+// validate_val returns the canonical form of v: "+001.500" => 1.5, "2e3" => 2000
+func (d *decimal_filter) validate_val(v string, op string, lang string) (raw_literal, error) {
+	var invalid = new_err(d.key, v, invalid_num_err(), lang, op)
+
+	// the Arabic decimal separator (٫) is the same as (.)
+	var num, canonical, ok = parse_decimal(strings.ReplaceAll(normalize_digits(v), "٫", "."))
+
+	if !ok {
+		return raw_literal{}, invalid
+	}
+
+	if d.check_prec {
+		var int_part, frac_part, _ = strings.Cut(strings.TrimPrefix(canonical, "-"), ".")
+
+		if len(frac_part) > d.scale {
+			return raw_literal{}, new_err(d.key, v, long_fraction_err(d.scale), lang, op)
+		}
+
+		if int_part == "0" {
+			int_part = ""
+		}
+
+		if len(int_part) > d.int_digits {
+			return raw_literal{}, new_err(d.key, v, long_integer_part_err(d.int_digits), lang, op)
+		}
+	}
+
+	if d.check_min && num.Cmp(d.min) < 0 {
+		return raw_literal{}, new_err(d.key, v, small_num_err(json.Number(d.s_min)), lang, op)
+	}
+
+	if d.check_max && num.Cmp(d.max) > 0 {
+		return raw_literal{}, new_err(d.key, v, large_num_err(json.Number(d.s_max)), lang, op)
+	}
+
+	var lit = raw_literal{
+		sql: canonical,
+		arg: canonical,
+	}
+
+	if d.bind_floats {
+		var f_num, err = strconv.ParseFloat(canonical, 64)
+		if err != nil {
+			return raw_literal{}, invalid
+		}
+		lit.arg = f_num
+	}
+
+	return lit, nil
+}
+
+// parse_decimal returns the exact value of s and its canonical form
+func parse_decimal(s string) (*big.Rat, string, bool) {
+	var parts = decimal_regexp.FindStringSubmatch(s)
+
+	if parts == nil {
+		return nil, "", false
+	}
+
+	var exp = 0
+	if parts[3] != "" {
+		var err error
+		if exp, err = strconv.Atoi(parts[3]); err != nil || exp > max_decimal_exp || exp < -max_decimal_exp {
+			return nil, "", false
+		}
+	}
+
+	var num, ok = new(big.Rat).SetString(s)
+
+	if !ok {
+		return nil, "", false
+	}
+
+	var frac_digits = 0
+	if dot := strings.IndexByte(parts[1], '.'); dot > -1 {
+		frac_digits = len(parts[1]) - dot - 1
+	}
+
+	frac_digits = max(frac_digits-exp, 0)
+
+	var canonical = num.FloatString(frac_digits)
+
+	if strings.IndexByte(canonical, '.') > -1 {
+		canonical = strings.TrimRight(strings.TrimRight(canonical, "0"), ".")
+	}
+
+	if canonical == "-0" {
+		canonical = "0"
+	}
+
+	return num, canonical, true
+}
+
+// decimal_bound returns the exact bound (of the decimal string if it is given, otherwise of f)
+func decimal_bound(f float64, s string) (*big.Rat, string, error) {
+	if s == "" {
+		s = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	// NaN and the infinities do not match the decimal syntax
+	var num, canonical, ok = parse_decimal(s)
+
+	if !ok {
+		return nil, "", errors.New("filter: invalid decimal bound (" + s + ")")
+	}
+
+	return num, canonical, nil
+}
+
+func long_fraction_err(scale int) message {
+	return message{code: "number.too_many_fraction_digits", params: map[string]any{"scale": scale}}
+}
+
+func long_integer_part_err(digits int) message {
+	return message{code: "number.too_many_integer_digits", params: map[string]any{"digits": digits}}
+}
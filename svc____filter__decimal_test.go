@@ -0,0 +1,266 @@
+package filter_test
+
+import (
+	"math"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/MaSTeR2W/filter"
+)
+
+func TestDecimalFilter(t *testing.T) {
+	const (
+		LANG_AR = "ar"
+		LANG_EN = "en"
+	)
+
+	var sql = "SELECT * FROM products"
+
+	var fs = filter.NewFilters(
+		filter.FilterConfigs{
+			SqlSelect: sql,
+		},
+		filter.MustCreateNewDecimalFilter(filter.DecimalFilterOpts{
+			Key:             "price",
+			EnableMin:       true,
+			Min:             0.1,
+			EnableMax:       true,
+			Max:             9999.99,
+			EnablePrecision: true,
+			Precision:       6,
+			Scale:           2,
+		}),
+	)
+
+	var test_canonical = func(t *testing.T) {
+		var inputs = map[string]string{
+			"+0012.50": "12.5",
+			"1e3":      "1000",
+			"2.5E-1":   "0.25",
+			".5":       "0.5",
+			"١٢٫٥":     "12.5",
+			"۷۵":       "75",
+			"7.":       "7",
+			"0.10":     "0.1",
+		}
+
+		for input, canonical := range inputs {
+			var v = url.Values{
+				"price[eq]": []string{input},
+			}
+
+			var query, err = fs.ValidateAndConstruct(v, LANG_AR)
+
+			if err != nil {
+				t.Error(input, err)
+				continue
+			}
+
+			if query != sql+" WHERE price="+canonical {
+				t.Error("invalid query:", query)
+			}
+		}
+	}
+
+	t.Run("test_canonical", test_canonical)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_range = func(t *testing.T) {
+		var v = url.Values{
+			"price[gte]": []string{"10"},
+			"price[lt]":  []string{"99.95"},
+		}
+
+		var query, args, err = fs.ValidateAndConstructWithArgs(v, LANG_AR)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != sql+" WHERE (price>=? AND price<?)" {
+			t.Error("invalid query:", query)
+		}
+
+		if !reflect.DeepEqual(args, []any{"10", "99.95"}) {
+			t.Error("invalid args:", args)
+		}
+	}
+
+	t.Run("test_range", test_range)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_float_args = func(t *testing.T) {
+		var fs = filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect: sql,
+			},
+			filter.MustCreateNewDecimalFilter(filter.DecimalFilterOpts{
+				Key:   "rating",
+				Float: true,
+			}),
+		)
+
+		var v = url.Values{
+			"rating[gt]": []string{"4.5"},
+		}
+
+		var query, args, err = fs.ValidateAndConstructWithArgs(v, LANG_AR)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != sql+" WHERE rating>?" {
+			t.Error("invalid query:", query)
+		}
+
+		if !reflect.DeepEqual(args, []any{4.5}) {
+			t.Error("invalid args:", args)
+		}
+	}
+
+	t.Run("test_float_args", test_float_args)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_exact_bounds = func(t *testing.T) {
+		var fs = filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect: sql,
+			},
+			filter.MustCreateNewDecimalFilter(filter.DecimalFilterOpts{
+				Key:        "balance",
+				EnableMax:  true,
+				MaxDecimal: "99999999999999999.99",
+				EnableMin:  true,
+				MinDecimal: "-0.010",
+			}),
+		)
+
+		var query, err = fs.ValidateAndConstruct(url.Values{"balance[lte]": []string{"99999999999999999.99"}}, LANG_EN)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != sql+" WHERE balance<=99999999999999999.99" {
+			t.Error("invalid query:", query)
+		}
+
+		// the float64 of the bound is 100000000000000000
+		_, err = fs.ValidateAndConstruct(url.Values{"balance[lte]": []string{"99999999999999999.991"}}, LANG_EN)
+
+		if err == nil || err.Error() != "[\nThe number should be less than or equal to 99999999999999999.99\n]" {
+			t.Error("invalid error:", err)
+		}
+
+		_, err = fs.ValidateAndConstruct(url.Values{"balance[gt]": []string{"-0.011"}}, LANG_EN)
+
+		if err == nil || err.Error() != "[\nThe number should be greater than or equal to -0.01\n]" {
+			t.Error("invalid error:", err)
+		}
+	}
+
+	t.Run("test_exact_bounds", test_exact_bounds)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_invalid_opts = func(t *testing.T) {
+		var cases = []filter.DecimalFilterOpts{
+			{Key: "balance", EnableMax: true, MaxDecimal: "1/3"},
+			{Key: "balance", EnableMin: true, Min: math.NaN()},
+			{Key: "balance", EnableMax: true, Max: math.Inf(1)},
+			{Key: "balance", EnablePrecision: true, Precision: 2, Scale: 4},
+		}
+
+		for _, opts := range cases {
+			if _, err := filter.NewDecimalFilter(opts); err == nil {
+				t.Error("should throw error:", opts)
+			}
+		}
+
+		defer func() {
+			if recover() == nil {
+				t.Error("should panic")
+			}
+		}()
+
+		filter.MustCreateNewDecimalFilter(cases[0])
+	}
+
+	t.Run("test_invalid_opts", test_invalid_opts)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_errors = func(t *testing.T) {
+		var cases = []struct {
+			input string
+			lang  string
+			msg   string
+		}{
+			{"NaN", LANG_EN, "invalid number"},
+			{"Inf", LANG_EN, "invalid number"},
+			{"-Infinity", LANG_EN, "invalid number"},
+			{"0x10", LANG_EN, "invalid number"},
+			{"1_000", LANG_EN, "invalid number"},
+			{"1/3", LANG_EN, "invalid number"},
+			{"1e999999999", LANG_EN, "invalid number"},
+			{"1.005", LANG_EN, "The number should not have more than 2 digits after the decimal point"},
+			{"12345.5", LANG_AR, "يجب ألا يتجاوز عدد الخانات قبل الفاصلة العشرية 4"},
+			{"0.09", LANG_EN, "The number should be greater than or equal to 0.1"},
+			{"9999.995e0", LANG_EN, "The number should not have more than 2 digits after the decimal point"},
+		}
+
+		for _, c := range cases {
+			var v = url.Values{
+				"price[lte]": []string{c.input},
+			}
+
+			var _, err = fs.ValidateAndConstruct(v, c.lang)
+
+			if err == nil {
+				t.Error("should throw error:", c.input)
+				continue
+			}
+
+			var f_err = (*err.(*filter.FilterErrs))[0].(*filter.FilterErr)
+
+			if f_err.Key != "price" || f_err.Value != c.input || f_err.Path[0] != "lte" || f_err.Message != c.msg {
+				t.Error("invalid error:", c.input, f_err)
+			}
+		}
+	}
+
+	t.Run("test_errors", test_errors)
+}
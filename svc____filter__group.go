@@ -0,0 +1,314 @@
+package filter
+
+import (
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// group is a node of the boolean tree built from the query keys:
+//
+//	$or[0][status][in]=1&$or[1][created][pr]=2024-01-01
+//	$not[status][in]=1
+//	$and[0][$or][0][status][in]=1&$and[0][$or][1][status][null]=1
+//
+// each node is the conjunction of its leaves and its sub groups
+type group struct {
+	leaves url.Values
+	ors    map[int]*group
+	ands   map[int]*group
+	not    *group
+}
+
+func new_group() *group {
+	return &group{
+		leaves: url.Values{},
+		ors:    map[int]*group{},
+		ands:   map[int]*group{},
+	}
+}
+
+type group_opts struct {
+	max_depth  int
+	max_leaves int
+}
+
+func new_group_opts(max_depth int, max_leaves int) *group_opts {
+	if max_depth < 1 {
+		max_depth = 3
+	}
+
+	if max_leaves < 1 {
+		max_leaves = 20
+	}
+
+	return &group_opts{
+		max_depth:  max_depth,
+		max_leaves: max_leaves,
+	}
+}
+
+func is_group_key(key string) bool {
+	return strings.HasPrefix(key, "$or[") ||
+		strings.HasPrefix(key, "$and[") ||
+		strings.HasPrefix(key, "$not[")
+}
+
+// parse_groups returns nil if v does not have any group key,
+// claims reports whether any filter recognizes a leaf (a typo in a leaf key would drop its branch silently)
+func (o *group_opts) parse_groups(
+	v url.Values,
+	lang string,
+	claims func(key string, vals []string) bool,
+) (*group, error) {
+	var keys = make([]string, 0)
+	for key := range v {
+		if is_group_key(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	slices.Sort(keys)
+
+	var root = new_group()
+	var leaves_num = 0
+
+	for _, key := range keys {
+		var tokens, ok = split_group_key(key)
+
+		if !ok {
+			return nil, new_err(key, OmitVal, invalid_group_err(), lang)
+		}
+
+		var depth int
+		if depth, ok = root.add(tokens, v[key], 0); !ok {
+			return nil, new_err(key, OmitVal, invalid_group_err(), lang)
+		}
+
+		if depth > o.max_depth {
+			return nil, new_err(key, OmitVal, group_depth_err(o.max_depth), lang)
+		}
+
+		if !claims(tokens[len(tokens)-2]+"["+tokens[len(tokens)-1]+"]", v[key]) {
+			return nil, new_err(key, OmitVal, invalid_group_err(), lang)
+		}
+
+		leaves_num++
+
+		if leaves_num > o.max_leaves {
+			return nil, new_err(key, OmitVal, group_leaves_err(o.max_leaves), lang)
+		}
+	}
+
+	return root, nil
+}
+
+// split_group_key splits "$or[0][status][in]" into ["$or", "0", "status", "in"]
+func split_group_key(key string) ([]string, bool) {
+	var start = strings.IndexByte(key, '[')
+
+	if start < 1 || key[len(key)-1] != ']' {
+		return nil, false
+	}
+
+	var tokens = []string{key[:start]}
+
+	for _, token := range strings.Split(key[start+1:len(key)-1], "][") {
+		if token == "" || strings.ContainsAny(token, "[]") {
+			return nil, false
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, true
+}
+
+// add returns the depth of the leaf
+func (g *group) add(tokens []string, vals []string, depth int) (int, bool) {
+	switch tokens[0] {
+	case "$or", "$and":
+		if len(tokens) < 3 {
+			return 0, false
+		}
+
+		var idx, err = strconv.Atoi(tokens[1])
+		if err != nil || idx < 0 {
+			return 0, false
+		}
+
+		var children = g.ors
+		if tokens[0] == "$and" {
+			children = g.ands
+		}
+
+		var child, ok = children[idx]
+		if !ok {
+			child = new_group()
+			children[idx] = child
+		}
+
+		return child.add(tokens[2:], vals, depth+1)
+
+	case "$not":
+		if len(tokens) < 2 {
+			return 0, false
+		}
+
+		if g.not == nil {
+			g.not = new_group()
+		}
+
+		return g.not.add(tokens[1:], vals, depth+1)
+	}
+
+	if len(tokens) != 2 {
+		return 0, false
+	}
+
+	g.leaves[tokens[0]+"["+tokens[1]+"]"] = vals
+
+	return depth, true
+}
+
+// construct_conds validates v against all the filters,
+// the errors of the filters inside a group are prefixed with the group path
+func (f *filters) construct_conds(v url.Values, lang string, ctx *SqlCtx, path []any, errs *FilterErrs) []string {
+	var conds = []string{}
+
+	for _, f := range f.filters {
+		var cond, err = f.ValidateAndConstruct(v, lang, ctx)
+
+		if err != nil {
+			*errs = append(*errs, prefix_err_path(err, path))
+			continue
+		}
+
+		if cond != "" {
+			conds = append(conds, cond)
+		}
+	}
+
+	return conds
+}
+
+func (f *filters) construct_group_conds(g *group, lang string, ctx *SqlCtx, path []any, errs *FilterErrs) []string {
+	var conds = []string{}
+
+	for _, idx := range sorted_keys(g.ands) {
+		var cond = f.construct_group(g.ands[idx], lang, ctx, append(slices.Clip(path), "$and", idx), errs)
+		if cond != "" {
+			conds = append(conds, cond)
+		}
+	}
+
+	// an empty branch would turn (A OR B) into (A)
+	var branches = []string{}
+	for _, idx := range sorted_keys(g.ors) {
+		var branch_path = append(slices.Clip(path), "$or", idx)
+		var errs_len = len(*errs)
+
+		var branch = f.construct_group(g.ors[idx], lang, ctx, branch_path, errs)
+
+		if branch != "" {
+			branches = append(branches, branch)
+		} else if len(*errs) == errs_len {
+			*errs = append(*errs, new_err("$or", OmitVal, invalid_group_err(), lang, branch_path...))
+		}
+	}
+
+	switch len(branches) {
+	case 0:
+	case 1:
+		conds = append(conds, branches[0])
+	default:
+		conds = append(conds, "("+strings.Join(branches, " OR ")+")")
+	}
+
+	if g.not != nil {
+		var not_path = append(slices.Clip(path), "$not")
+		var not_conds = append(
+			f.construct_conds(g.not.leaves, lang, ctx, not_path, errs),
+			f.construct_group_conds(g.not, lang, ctx, not_path, errs)...,
+		)
+
+		if len(not_conds) > 0 {
+			conds = append(conds, "NOT ("+strings.Join(not_conds, " AND ")+")")
+		}
+	}
+
+	return conds
+}
+
+func (f *filters) construct_group(g *group, lang string, ctx *SqlCtx, path []any, errs *FilterErrs) string {
+	var conds = append(
+		f.construct_conds(g.leaves, lang, ctx, path, errs),
+		f.construct_group_conds(g, lang, ctx, path, errs)...,
+	)
+
+	switch len(conds) {
+	case 0:
+		return ""
+	case 1:
+		return conds[0]
+	}
+
+	return "(" + strings.Join(conds, " AND ") + ")"
+}
+
+// claims reports whether any filter constructs a condition (or returns an error) for the single leaf key,
+// the filters run against a separate ctx so the args of the query are not affected
+func (f *filters) claims(key string, vals []string, lang string) bool {
+	var probe = f.new_ctx(false)
+
+	for _, ft := range f.filters {
+		var cond, err = ft.ValidateAndConstruct(url.Values{key: vals}, lang, probe)
+
+		if err != nil || cond != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func prefix_err_path(err error, path []any) error {
+	if len(path) == 0 {
+		return err
+	}
+
+	var f_err, ok = err.(*FilterErr)
+	if !ok {
+		return err
+	}
+
+	var prefixed = *f_err
+	prefixed.Path = append(slices.Clip(path), f_err.Path...)
+
+	return &prefixed
+}
+
+func sorted_keys(m map[int]*group) []int {
+	var keys = make([]int, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func invalid_group_err() message {
+	return message{code: "group.invalid"}
+}
+
+func group_depth_err(max int) message {
+	return message{code: "group.too_deep", params: map[string]any{"max": max}}
+}
+
+func group_leaves_err(max int) message {
+	return message{code: "group.too_many_conditions", params: map[string]any{"max": max}}
+}
@@ -0,0 +1,245 @@
+package filter_test
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/MaSTeR2W/filter"
+)
+
+func TestGroupFilter(t *testing.T) {
+	const (
+		LANG_AR = "ar"
+		LANG_EN = "en"
+	)
+
+	var sql = "SELECT * FROM users"
+
+	var fs = filter.NewFilters(
+		filter.FilterConfigs{
+			SqlSelect:   sql,
+			Groups:      true,
+			GroupDepth:  2,
+			GroupLeaves: 4,
+		},
+		filter.NewCheckboxIntFilter(filter.CheckboxIntFilterOpts{
+			Key:     "status",
+			Opts:    []int{1, 2, 3},
+			NullOpt: true,
+		}),
+		filter.MustCreateNewDateFilter(filter.DateFilterOpts{
+			Key: "created",
+		}),
+		filter.NewStrFilter(filter.StrFilterOpts{
+			Key: "name",
+		}),
+	)
+
+	var test_or = func(t *testing.T) {
+		var v = url.Values{
+			"name[sw]":             []string{"m"},
+			"$or[0][status][in]":   []string{"1", "2"},
+			"$or[1][created][pr]":  []string{"2024-01-01"},
+			"$or[1][status][null]": []string{"1"},
+		}
+
+		var query, err = fs.ValidateAndConstruct(v, LANG_AR)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != sql+" WHERE name LIKE 'm%' AND (status IN (1,2) OR (status IS NULL AND created<'2024-01-01'))" {
+			t.Error("invalid query:", query)
+		}
+	}
+
+	t.Run("test_or", test_or)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_not = func(t *testing.T) {
+		var v = url.Values{
+			"$not[status][in]":          []string{"3"},
+			"$and[0][$or][0][name][eq]": []string{"a"},
+			"$and[0][$or][1][name][eq]": []string{"b"},
+		}
+
+		var query, args, err = fs.ValidateAndConstructWithArgs(v, LANG_AR)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != sql+" WHERE (name=? OR name=?) AND NOT (status IN (?))" {
+			t.Error("invalid query:", query)
+		}
+
+		if !reflect.DeepEqual(args, []any{"a", "b", 3}) {
+			t.Error("invalid args:", args)
+		}
+	}
+
+	t.Run("test_not", test_not)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_leaf_err_path = func(t *testing.T) {
+		var v = url.Values{
+			"$or[0][status][in]":  []string{"1"},
+			"$or[1][created][eq]": []string{"2024-13-01"},
+		}
+
+		var _, err = fs.ValidateAndConstruct(v, LANG_EN)
+
+		if err == nil {
+			t.Error("should throw error")
+			return
+		}
+
+		var f_err = (*err.(*filter.FilterErrs))[0].(*filter.FilterErr)
+
+		if f_err.Key != "created" {
+			t.Error("invalid key:", f_err.Key)
+		}
+
+		if !reflect.DeepEqual(f_err.Path, []any{"$or", 1, "eq"}) {
+			t.Error("invalid path:", f_err.Path)
+		}
+	}
+
+	t.Run("test_leaf_err_path", test_leaf_err_path)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_limits = func(t *testing.T) {
+		var v = url.Values{
+			"$or[0][$or][0][$not][status][in]": []string{"1"},
+		}
+
+		var _, err = fs.ValidateAndConstruct(v, LANG_EN)
+
+		if err == nil {
+			t.Error("should throw error")
+			return
+		}
+
+		var f_err = (*err.(*filter.FilterErrs))[0].(*filter.FilterErr)
+
+		if f_err.Message != "Groups cannot be nested more than 2 levels" {
+			t.Error("invalid message:", f_err.Message)
+		}
+
+		v = url.Values{
+			"$or[0][name][eq]": []string{"a"},
+			"$or[1][name][eq]": []string{"b"},
+			"$or[2][name][eq]": []string{"c"},
+			"$or[3][name][eq]": []string{"d"},
+			"$or[4][name][eq]": []string{"e"},
+		}
+
+		_, err = fs.ValidateAndConstruct(v, LANG_AR)
+
+		if err == nil {
+			t.Error("should throw error")
+			return
+		}
+
+		f_err = (*err.(*filter.FilterErrs))[0].(*filter.FilterErr)
+
+		if f_err.Message != "لا يمكن أن يتجاوز عدد الشروط داخل المجموعات 4" {
+			t.Error("invalid message:", f_err.Message)
+		}
+
+		v = url.Values{
+			"$or[x][name][eq]": []string{"a"},
+		}
+
+		_, err = fs.ValidateAndConstruct(v, LANG_EN)
+
+		if err == nil {
+			t.Error("should throw error")
+			return
+		}
+
+		f_err = (*err.(*filter.FilterErrs))[0].(*filter.FilterErr)
+
+		if f_err.Key != "$or[x][name][eq]" || f_err.Message != "The group syntax is invalid" {
+			t.Error("invalid error:", f_err)
+		}
+
+		// a leaf that no filter recognizes (a typo) would drop its branch
+		v = url.Values{
+			"$or[0][name][eq]":  []string{"a"},
+			"$or[1][namee][eq]": []string{"b"},
+		}
+
+		_, err = fs.ValidateAndConstruct(v, LANG_EN)
+
+		if err == nil {
+			t.Error("should throw error")
+			return
+		}
+
+		f_err = (*err.(*filter.FilterErrs))[0].(*filter.FilterErr)
+
+		if f_err.Key != "$or[1][namee][eq]" || f_err.Code != "group.invalid" {
+			t.Error("invalid error:", f_err)
+		}
+	}
+
+	t.Run("test_limits", test_limits)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_groups_disabled = func(t *testing.T) {
+		var fs = filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect: sql,
+			},
+			filter.NewStrFilter(filter.StrFilterOpts{
+				Key: "name",
+			}),
+		)
+
+		var v = url.Values{
+			"$or[0][name][eq]": []string{"a"},
+		}
+
+		var query, err = fs.ValidateAndConstruct(v, LANG_EN)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if query != sql {
+			t.Error("invalid query:", query)
+		}
+	}
+
+	t.Run("test_groups_disabled", test_groups_disabled)
+}
@@ -14,8 +14,7 @@ type int_filter struct {
 	min       int
 	check_max bool
 	check_min bool
-	s_max     string
-	s_min     string
+	null_opt  bool
 }
 
 type IntFilterOpts struct {
@@ -25,6 +24,7 @@ type IntFilterOpts struct {
 	Max       int
 	EnableMin bool
 	Min       int
+	NullOpt   bool
 }
 
 func NewIntFilter(
@@ -37,70 +37,85 @@ func NewIntFilter(
 	var f = int_filter{
 		key:       opts.Key,
 		col_alias: opts.ColAlias,
+		null_opt:  opts.NullOpt,
 	}
 
 	if opts.EnableMax {
 		f.check_max = true
 		f.max = opts.Max
-		f.s_max = strconv.Itoa(opts.Max)
 	}
 
 	if opts.EnableMin {
 		f.check_min = true
 		f.min = opts.Min
-		f.s_min = strconv.Itoa(opts.Min)
 	}
 
 	return &f
 }
 
-func (i *int_filter) validate_and_construct(
+func (i *int_filter) ValidateAndConstruct(
 	v url.Values,
 	lang string,
+	ctx *SqlCtx,
+) (string, error) {
+	var cond, err = i.validate_and_construct_vals(v, lang, ctx)
+
+	if err != nil {
+		return "", err
+	}
+
+	if i.null_opt {
+		cond = with_null_cond(v, i.key, ctx.Ident(i.col_alias), cond)
+	}
+
+	return cond, nil
+}
+
+func (i *int_filter) validate_and_construct_vals(
+	v url.Values,
+	lang string,
+	ctx *SqlCtx,
 ) (string, error) {
 
 	var val string
+	var num int
 	var ok bool
 	var err error
 
 	if val, ok = get_first_el_if_exists(v, i.key+"[eq]"); ok {
-		if val, err = i.validate_val(val, lang); err != nil {
+		if num, err = i.validate_val(val, lang); err != nil {
 			return "", err
 		}
 
-		return i.col_alias + "=" + val, nil
-	}
-
-	if _, ok = v[i.key+"[null]"]; ok {
-		return i.col_alias + "=NULL", nil
+		return ctx.Ident(i.col_alias) + "=" + ctx.Bind(num), nil
 	}
 
 	var conds = []string{}
 
 	if val, ok = get_first_el_if_exists(v, i.key+"[gt]"); ok {
-		if val, err = i.validate_val(val, lang); err != nil {
+		if num, err = i.validate_val(val, lang); err != nil {
 			return "", err
 		}
 
-		conds = append(conds, i.col_alias+">"+val)
+		conds = append(conds, ctx.Ident(i.col_alias)+">"+ctx.Bind(num))
 	} else if val, ok = get_first_el_if_exists(v, i.key+"[gte]"); ok {
-		if val, err = i.validate_val(val, lang); err != nil {
+		if num, err = i.validate_val(val, lang); err != nil {
 			return "", err
 		}
-		conds = append(conds, i.col_alias+">="+val)
+		conds = append(conds, ctx.Ident(i.col_alias)+">="+ctx.Bind(num))
 	}
 
 	if val, ok = get_first_el_if_exists(v, i.key+"[lt]"); ok {
-		if val, err = i.validate_val(val, lang); err != nil {
+		if num, err = i.validate_val(val, lang); err != nil {
 			return "", err
 		}
-		conds = append(conds, i.col_alias+"<"+val)
+		conds = append(conds, ctx.Ident(i.col_alias)+"<"+ctx.Bind(num))
 
 	} else if val, ok = get_first_el_if_exists(v, i.key+"[lte]"); ok {
-		if val, err = i.validate_val(val, lang); err != nil {
+		if num, err = i.validate_val(val, lang); err != nil {
 			return "", err
 		}
-		conds = append(conds, i.col_alias+"<="+val)
+		conds = append(conds, ctx.Ident(i.col_alias)+"<="+ctx.Bind(num))
 
 	}
 
@@ -117,54 +132,32 @@ func (i *int_filter) validate_and_construct(
 	return "(" + conds[0] + " AND " + conds[1] + ")", nil
 }
 
-func (i *int_filter) validate_val(v string, lang string) (string, error) {
-	var num, err = strconv.Atoi(v)
+func (i *int_filter) validate_val(v string, lang string) (int, error) {
+	var num, err = strconv.Atoi(normalize_digits(v))
 
 	if err != nil {
-		return "", &FilterErr{
-			Key:     i.key,
-			Value:   v,
-			Message: invalid_num_err(lang),
-		}
+		return 0, new_err(i.key, v, invalid_num_err(), lang)
 	}
 
 	if i.check_min && num < i.min {
-		return "", &FilterErr{
-			Key:     i.key,
-			Value:   v,
-			Message: small_num_err(i.s_min, lang),
-		}
+		return 0, new_err(i.key, v, small_num_err(i.min), lang)
 	}
 
 	if i.check_max && num > i.max {
-		return "", &FilterErr{
-			Key:     i.key,
-			Value:   v,
-			Message: large_num_err(i.s_max, lang),
-		}
+		return 0, new_err(i.key, v, large_num_err(i.max), lang)
 	}
 
-	return strconv.Itoa(num), nil
+	return num, nil
 }
 
-func invalid_num_err(lang string) string {
-	if lang == "ar" {
-		return "عدد غير صالح"
-	}
-	return "invalid number"
+func invalid_num_err() message {
+	return message{code: "number.invalid"}
 }
 
-func small_num_err(s_exp string, lang string) string {
-	if lang == "ar" {
-		return "يجب أن يكون العدد أكبر من أو يساوي " + s_exp
-	}
-	return "The number should be greater than or equal to " + s_exp
+func small_num_err(min any) message {
+	return message{code: "number.too_small", params: map[string]any{"min": min}}
 }
 
-func large_num_err(s_exp string, lang string) string {
-	if lang == "ar" {
-		return "يجب أن يكون العدد أصغر من أو يساوي " + s_exp
-	}
-
-	return "The number should be less than or equal to " + s_exp
+func large_num_err(max any) message {
+	return message{code: "number.too_large", params: map[string]any{"max": max}}
 }
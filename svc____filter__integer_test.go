@@ -117,20 +117,57 @@ func TestIntFilter(t *testing.T) {
 	}
 
 	var test_int_filter_null = func(t *testing.T) {
-		var vals = url.Values{
-			"age[null]": []string{"8"},
-		}
-
-		var query, err = fs.ValidateAndConstruct(vals, langAr)
+		var fs = filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect: sql,
+			},
+			filter.NewIntFilter(filter.IntFilterOpts{
+				Key:     "age",
+				NullOpt: true,
+			}),
+		)
+
+		var cases = []struct {
+			vals  url.Values
+			query string
+		}{
+			{url.Values{"age[null]": []string{"8"}}, sql + " WHERE age IS NULL"},
+			{url.Values{"age[null]": []string{"0"}}, sql + " WHERE age IS NOT NULL"},
+			{url.Values{"age[null]": []string{"٠"}}, sql + " WHERE age IS NOT NULL"},
+			{url.Values{"age[null]": []string{"1"}, "age[gt]": []string{"8"}}, sql + " WHERE (age>8 OR age IS NULL)"},
+			{url.Values{"age[null]": []string{"0"}, "age[gt]": []string{"8"}}, sql + " WHERE age>8"},
+		}
+
+		for _, c := range cases {
+			var query, err = fs.ValidateAndConstruct(c.vals, langAr)
+
+			if err != nil {
+				t.Error("error should be nil: ", err)
+				continue
+			}
+
+			if query != c.query {
+				t.Error("invalid query:", query)
+			}
+		}
+
+		// without NullOpt
+		var query, err = filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect: sql,
+			},
+			filter.NewIntFilter(filter.IntFilterOpts{
+				Key: "age",
+			}),
+		).ValidateAndConstruct(url.Values{"age[null]": []string{"1"}}, langAr)
 
 		if err != nil {
 			t.Error("error should be nil: ", err)
 			return
 		}
 
-		if query != sql+" WHERE age=NULL" {
+		if query != sql {
 			t.Error("invalid query:", query)
-			return
 		}
 	}
 
@@ -356,6 +393,56 @@ func TestIntFilter(t *testing.T) {
 		}
 	}
 
+	var test_int_filter_arabic_digits = func(t *testing.T) {
+		var vals = url.Values{
+			"age[gte]": []string{"٣"},
+			"age[lt]":  []string{"۱۰"},
+		}
+
+		var query, err = fs.ValidateAndConstruct(vals, langAr)
+
+		if err != nil {
+			t.Error("error should be nil: ", err)
+			return
+		}
+
+		if query != sql+" WHERE (age>=3 AND age<10)" {
+			t.Error("invalid query:", query)
+			return
+		}
+	}
+
+	var test_int_filter_localized_err_digits = func(t *testing.T) {
+		var fs = filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect:    sql,
+				ArabicDigits: true,
+			},
+			filter.NewIntFilter(filter.IntFilterOpts{
+				Key:       "age",
+				EnableMax: true,
+				Max:       10,
+			}),
+		)
+
+		var vals = url.Values{
+			"age[eq]": []string{"١٢"},
+		}
+
+		var _, err = fs.ValidateAndConstruct(vals, langAr)
+
+		if err == nil || err.Error() != "[\nيجب أن يكون العدد أصغر من أو يساوي ١٠\n]" {
+			t.Error(err)
+		}
+
+		// the other languages are not affected
+		_, err = fs.ValidateAndConstruct(vals, langEn)
+
+		if err == nil || err.Error() != "[\nThe number should be less than or equal to 10\n]" {
+			t.Error(err)
+		}
+	}
+
 	t.Run("test_int_filter_eq", test_int_filter_eq)
 	t.Run("test_int_filter_gt", test_int_filter_gt)
 	t.Run("test_int_filter_gte", test_int_filter_gte)
@@ -372,4 +459,6 @@ func TestIntFilter(t *testing.T) {
 	t.Run("test_int_filter_invalid_num_en", test_int_filter_invalid_num_en)
 	t.Run("test_int_filter_large_num_err_en", test_int_filter_large_num_err_en)
 	t.Run("test_int_filter_small_num_err_en", test_int_filter_small_num_err_en)
+	t.Run("test_int_filter_arabic_digits", test_int_filter_arabic_digits)
+	t.Run("test_int_filter_localized_err_digits", test_int_filter_localized_err_digits)
 }
@@ -6,68 +6,188 @@ import (
 	"strings"
 )
 
+type NullsOrder int
+
+const (
+	NullsDefault NullsOrder = iota // the default of the database
+	NullsFirst
+	NullsLast
+)
+
+type OrderByOpts struct {
+	Key      string     // what the client sends in ($order_by)
+	ColAlias string     // column or SQL expression, e.g. COALESCE(updated_at, created_at) (defaults to Key)
+	Nulls    NullsOrder // NULL values are not supported by cursor pagination
+	Desc     bool       // direction used when neither "-" nor ($arrange) is given
+}
+
 type orderer struct {
-	cols   []string
-	s_cols string
+	cols         []string
+	opts         map[string]OrderByOpts
+	max_keys     int
+	default_keys []sort_key
 }
 
 type orderer_opts struct {
-	cols []string
+	cols         []OrderByOpts
+	max_keys     int
+	default_keys []string
+}
+
+type sort_key struct {
+	key   string // public key
+	expr  string
+	desc  bool
+	nulls NullsOrder
 }
 
 func new_orderer(opts orderer_opts) *orderer {
-	return &orderer{
-		cols:   opts.cols,
-		s_cols: strings.Join(opts.cols, ", "),
+	if opts.max_keys < 1 {
+		opts.max_keys = len(opts.cols)
+	}
+
+	var o = orderer{
+		cols:     make([]string, 0, len(opts.cols)),
+		opts:     make(map[string]OrderByOpts, len(opts.cols)),
+		max_keys: opts.max_keys,
 	}
-}
 
-func (o *orderer) order(v url.Values, lang string) (string, error) {
-	var order_by, arrange, ok, err = o.get_order_by_arrange(v, lang)
+	for _, col := range opts.cols {
+		if col.ColAlias == "" {
+			col.ColAlias = col.Key
+		}
 
-	if err != nil {
-		return "", err
+		o.cols = append(o.cols, col.Key)
+		o.opts[col.Key] = col
 	}
 
+	if len(opts.default_keys) > 0 {
+		var err error
+		if o.default_keys, err = o.parse_sort_keys(opts.default_keys, "", false, "en"); err != nil {
+			panic("filter: invalid default sort key: " + err.Error())
+		}
+	}
+
+	return &o
+}
+
+// sort_keys returns the keys requested by ($order_by, $arrange), or the default keys if there is not any,
+// ($order_by) may be repeated or a comma separated list: $order_by=lastName,-createdAt
+// the keys prefixed with "-" are descending and the ones prefixed with "+" are ascending,
+// the others follow ($arrange) if it is given or the default direction of the key
+func (o *orderer) sort_keys(v url.Values, lang string) ([]sort_key, error) {
+	var vals, ok = get_val_if_exists(v, "$order_by")
+
 	if !ok {
-		return "", nil
+		return o.default_keys, nil
+	}
+
+	var entries = []string{}
+	for _, val := range vals {
+		for _, entry := range strings.Split(val, ",") {
+			// the empty entries of a trailing or a doubled comma (lastName,) are skipped
+			if entry = strings.TrimSpace(entry); entry != "" {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	if len(entries) == 0 {
+		return o.default_keys, nil
+	}
+
+	if len(entries) > o.max_keys {
+		return nil, new_err("$order_by", vals, too_many_sort_keys_err(o.max_keys), lang)
 	}
 
-	return "ORDER BY " + order_by + " " + arrange, nil
+	var arrange, ok_arrange = get_first_el_if_exists(v, "$arrange")
+
+	return o.parse_sort_keys(entries, arrange, ok_arrange, lang)
 }
 
-func (o *orderer) get_order_by_arrange(v url.Values, lang string) (string, string, bool, error) {
-	var order_by string
+func (o *orderer) parse_sort_keys(entries []string, arrange string, ok_arrange bool, lang string) ([]sort_key, error) {
+	var keys = make([]sort_key, 0, len(entries))
 	var ok bool
 
-	if order_by, ok = get_first_el_if_exists(v, "$order_by"); ok {
-		if !slices.Contains(o.cols, order_by) {
-			return "", "", false, &FilterErr{
-				Key:     "$order_by",
-				Value:   order_by,
-				Message: is_not_one_of_err(o.s_cols, lang),
+	for idx, entry := range entries {
+		var col = strings.TrimLeft(entry, "+-")
+		var opts OrderByOpts
+
+		// only one sign is allowed
+		if len(entry)-len(col) > 1 {
+			col = entry
+		}
+
+		if opts, ok = o.opts[col]; !ok {
+			return nil, new_err("$order_by", entry, is_not_one_of_err(o.cols), lang, idx)
+		}
+
+		var key = sort_key{
+			key:   opts.Key,
+			expr:  opts.ColAlias,
+			desc:  opts.Desc,
+			nulls: opts.Nulls,
+		}
+
+		switch {
+		case entry[0] == '-':
+			key.desc = true
+		case entry[0] == '+':
+			key.desc = false
+		case ok_arrange:
+			key.desc = arrange == "DESC"
+		}
+
+		for _, prev := range keys {
+			if prev.key == key.key {
+				return nil, new_err("$order_by", entry, duplicate_sort_key_err(key.key), lang, idx)
 			}
 		}
-	} else {
-		return "", "", false, nil
+
+		keys = append(keys, key)
 	}
 
-	var arrange string
+	return keys, nil
+}
 
-	if arrange, ok = get_first_el_if_exists(v, "$arrange"); ok {
-		if arrange != "DESC" {
-			arrange = "ASC"
+// append_tie_breaker appends the tie breaker (with the direction of the last key) if it is not already sorted by
+func append_tie_breaker(keys []sort_key, tie_breaker string) []sort_key {
+	for _, key := range keys {
+		if key.expr == tie_breaker {
+			return keys
 		}
-	} else {
-		arrange = "ASC"
 	}
 
-	return order_by, arrange, true, nil
+	var desc = false
+	if len(keys) > 0 {
+		desc = keys[len(keys)-1].desc
+	}
+
+	return append(slices.Clip(keys), sort_key{key: tie_breaker, expr: tie_breaker, desc: desc})
 }
 
-func is_not_one_of_err(one_of string, lang string) string {
-	if lang == "ar" {
-		return "يجب اختيار واحد مما يلي: (" + one_of + ")"
+func construct_order_by(keys []sort_key, ctx *SqlCtx) string {
+	if len(keys) == 0 {
+		return ""
+	}
+
+	var cols = make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		cols = append(cols, ctx.dialect.SortKey(ctx.Ident(key.expr), key.desc, key.nulls))
 	}
-	return "Should select one of the following: (" + one_of + ")"
+
+	return "ORDER BY " + strings.Join(cols, ", ")
+}
+
+func is_not_one_of_err(one_of []string) message {
+	return message{code: "sort.not_allowed", params: map[string]any{"columns": one_of}}
+}
+
+func too_many_sort_keys_err(max int) message {
+	return message{code: "sort.too_many_keys", params: map[string]any{"max": max}}
+}
+
+func duplicate_sort_key_err(col string) message {
+	return message{code: "sort.duplicate_key", params: map[string]any{"column": col}}
 }
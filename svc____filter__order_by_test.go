@@ -207,4 +207,280 @@ func TestOrderBy(t *testing.T) {
 	//
 	//
 	//
+
+	var test_multi_cols = func(t *testing.T) {
+		var fs = filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect:  "SELECT * FROM users",
+				OrderBy:    []string{"firstName", "lastName", "createdAt"},
+				OrderByMax: 2,
+			},
+		)
+
+		var v = url.Values{
+			"$order_by": []string{"lastName, -createdAt"},
+		}
+
+		var query, err = fs.ValidateAndConstruct(v, LANG_AR)
+
+		if err != nil {
+			t.Error("error should be nil:", err)
+			return
+		}
+
+		if query != "SELECT * FROM users ORDER BY lastName ASC, createdAt DESC" {
+			t.Error("invalid query:", query)
+			return
+		}
+
+		v = url.Values{
+			"$order_by": []string{"-lastName", "firstName"},
+			"$arrange":  []string{"DESC"},
+		}
+
+		query, err = fs.ValidateAndConstruct(v, LANG_AR)
+
+		if err != nil {
+			t.Error("error should be nil:", err)
+			return
+		}
+
+		if query != "SELECT * FROM users ORDER BY lastName DESC, firstName DESC" {
+			t.Error("invalid query:", query)
+			return
+		}
+
+		// the empty entries are not counted
+		v = url.Values{
+			"$order_by": []string{"lastName,,-createdAt,"},
+		}
+
+		query, err = fs.ValidateAndConstruct(v, LANG_EN)
+
+		if err != nil {
+			t.Error("error should be nil:", err)
+			return
+		}
+
+		if query != "SELECT * FROM users ORDER BY lastName ASC, createdAt DESC" {
+			t.Error("invalid query:", query)
+			return
+		}
+
+		v = url.Values{
+			"$order_by": []string{"lastName,firstName,createdAt"},
+		}
+
+		_, err = fs.ValidateAndConstruct(v, LANG_EN)
+
+		if err == nil {
+			t.Error("should return error")
+			return
+		}
+
+		var f_err = (*err.(*filter.FilterErrs))[0].(*filter.FilterErr)
+
+		if f_err.Key != "$order_by" || f_err.Message != "Cannot sort by more than 2 columns" {
+			t.Error("invalid error:", f_err)
+		}
+
+		v = url.Values{
+			"$order_by": []string{"lastName", "-lastName"},
+		}
+
+		_, err = fs.ValidateAndConstruct(v, LANG_AR)
+
+		if err == nil {
+			t.Error("should return error")
+			return
+		}
+
+		f_err = (*err.(*filter.FilterErrs))[0].(*filter.FilterErr)
+
+		if f_err.Message != "لا يمكن تكرار العمود (lastName)" || f_err.Value != "-lastName" || f_err.Path[0] != 1 {
+			t.Error("invalid error:", f_err)
+		}
+	}
+
+	t.Run("test_multi_cols", test_multi_cols)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_order_by_opts = func(t *testing.T) {
+		var new_filters = func(dialect filter.Dialect) interface {
+			ValidateAndConstruct(url.Values, string) (string, error)
+		} {
+			return filter.NewFilters(
+				filter.FilterConfigs{
+					SqlSelect:   "SELECT * FROM users",
+					Dialect:     dialect,
+					QuoteIdents: true,
+					OrderBy:     []string{"u.firstName"},
+					OrderByOpts: []filter.OrderByOpts{
+						{
+							Key:      "name",
+							ColAlias: "lower(last_name)",
+						},
+						{
+							Key:      "modified",
+							ColAlias: "COALESCE(updated_at, created_at)",
+							Nulls:    filter.NullsLast,
+							Desc:     true,
+						},
+					},
+				},
+			)
+		}
+
+		var v = url.Values{
+			"$order_by": []string{"modified,name,u.firstName"},
+		}
+
+		var query, err = new_filters(filter.Postgres).ValidateAndConstruct(v, LANG_EN)
+
+		if err != nil {
+			t.Error("error should be nil:", err)
+			return
+		}
+
+		if query != `SELECT * FROM users ORDER BY COALESCE(updated_at, created_at) DESC NULLS LAST, lower(last_name) ASC, "u"."firstName" ASC` {
+			t.Error("invalid query:", query)
+		}
+
+		v = url.Values{
+			"$order_by": []string{" modified"},
+			"$arrange":  []string{"ASC"},
+		}
+
+		query, err = new_filters(filter.MySQL).ValidateAndConstruct(v, LANG_EN)
+
+		if err != nil {
+			t.Error("error should be nil:", err)
+			return
+		}
+
+		if query != "SELECT * FROM users ORDER BY CASE WHEN COALESCE(updated_at, created_at) IS NULL THEN 1 ELSE 0 END, COALESCE(updated_at, created_at) ASC" {
+			t.Error("invalid query:", query)
+		}
+
+		v = url.Values{
+			"$order_by": []string{"last_name"},
+		}
+
+		_, err = new_filters(filter.MySQL).ValidateAndConstruct(v, LANG_EN)
+
+		if err == nil {
+			t.Error("should return error")
+			return
+		}
+
+		test_fields(
+			t,
+			(*err.(*filter.FilterErrs))[0].(*filter.FilterErr),
+			"$order_by",
+			"last_name",
+			"Should select one of the following: (u.firstName, name, modified)",
+		)
+	}
+
+	t.Run("test_order_by_opts", test_order_by_opts)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_default_tie_breaker = func(t *testing.T) {
+		var fs = filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect:      "SELECT * FROM users",
+				Paginate:       true,
+				OrderBy:        []string{"firstName", "createdAt"},
+				OrderByDefault: []string{"-createdAt"},
+				TieBreaker:     "id",
+			},
+		)
+
+		var v = url.Values{
+			"$limit": []string{"10"},
+			"$page":  []string{"2"},
+		}
+
+		var query, err = fs.ValidateAndConstruct(v, LANG_AR)
+
+		if err != nil {
+			t.Error("error should be nil:", err)
+			return
+		}
+
+		if query != "SELECT * FROM users ORDER BY createdAt DESC, id DESC LIMIT 10 OFFSET 10" {
+			t.Error("invalid query:", query)
+		}
+
+		v.Set("$order_by", "firstName")
+
+		query, err = fs.ValidateAndConstruct(v, LANG_AR)
+
+		if err != nil {
+			t.Error("error should be nil:", err)
+			return
+		}
+
+		if query != "SELECT * FROM users ORDER BY firstName ASC, id ASC LIMIT 10 OFFSET 10" {
+			t.Error("invalid query:", query)
+		}
+
+		// without sortable keys
+		fs = filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect:  "SELECT * FROM users",
+				TieBreaker: "id",
+			},
+		)
+
+		query, err = fs.ValidateAndConstruct(url.Values{}, LANG_AR)
+
+		if err != nil {
+			t.Error("error should be nil:", err)
+			return
+		}
+
+		if query != "SELECT * FROM users ORDER BY id ASC" {
+			t.Error("invalid query:", query)
+		}
+	}
+
+	t.Run("test_default_tie_breaker", test_default_tie_breaker)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_invalid_default = func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("should panic")
+			}
+		}()
+
+		filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect:      "SELECT * FROM users",
+				OrderBy:        []string{"firstName"},
+				OrderByDefault: []string{"lastName"},
+			},
+		)
+	}
+
+	t.Run("test_invalid_default", test_invalid_default)
 }
@@ -43,6 +43,34 @@ func TestPageLimitFilter(t *testing.T) {
 
 	t.Run("test_page_limit", test_page_limit)
 
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_arabic_digits = func(t *testing.T) {
+		var v = url.Values{
+			"$page":  []string{"٣"},
+			"$limit": []string{"۱۲"},
+		}
+
+		var query, err = fs.ValidateAndConstruct(v, LANG_AR)
+
+		if err != nil {
+			t.Error("error should be nil:", err)
+			return
+		}
+
+		if query != "SELECT * FROM users LIMIT 12 OFFSET 24" {
+			t.Error("invalid query:", query)
+			return
+		}
+	}
+
+	t.Run("test_arabic_digits", test_arabic_digits)
+
 	//
 	//
 	//
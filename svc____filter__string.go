@@ -2,22 +2,51 @@ package filter
 
 import (
 	"net/url"
-	"strconv"
+	"strings"
+	"unicode"
 )
 
 type str_filter struct {
-	key           string
-	col_alias     string
-	check_max_len bool
-	max_len       int
-	s_max_len     string
+	key              string
+	col_alias        string
+	len_rule         StrLenRule
+	op_len_rules     map[string]StrLenRule
+	normalize_arabic bool
+	normalized_col   string
+	max_vals         int
+	match_all        bool
+	null_opt         bool
+}
+
+// StrLenRule limits the length of the input (in characters, the combining marks are not counted)
+type StrLenRule struct {
+	EnableMinLen bool
+	MinLen       int
+	EnableMaxLen bool
+	MaxLen       int
 }
 
 type StrFilterOpts struct {
 	Key          string
 	ColAlias     string
+	EnableMinLen bool
+	MinLen       int
 	EnableMaxLen bool
 	MaxLen       int
+	// LenRules replace (MinLen, MaxLen) for the operators in it:
+	// {"ct": {EnableMinLen: true, MinLen: 3}} stops the single character (ct) searches
+	LenRules map[string]StrLenRule
+	// NormalizeArabic folds the variants of alef (أ إ آ => ا), taa marbuta (ة => ه) and alef maqsura (ى => ي)
+	// and removes the tashkeel and the tatweel of the input, so احمد matches أَحْمَـد
+	NormalizeArabic bool
+	// NormalizedCol is compared with the normalized input: a shadow column that holds the normalized value
+	// or an SQL expression like normalize_ar(name) (default: the column through nested REPLACE calls)
+	NormalizedCol string
+	// MaxValues is the maximum number of the values of a single operator: name[in]=a&name[in]=b (default: 10)
+	MaxValues int
+	// MatchAll joins the repeated values of (sw, ew, ct, ieq, isw, iew, ict) with AND (default: OR)
+	MatchAll bool
+	NullOpt  bool
 }
 
 func NewStrFilter(opts StrFilterOpts) *str_filter {
@@ -25,88 +54,242 @@ func NewStrFilter(opts StrFilterOpts) *str_filter {
 		opts.ColAlias = opts.Key
 	}
 
+	if opts.MaxValues <= 0 {
+		opts.MaxValues = 10
+	}
+
 	var f = str_filter{
 		key:       opts.Key,
 		col_alias: opts.ColAlias,
-	}
-
-	if opts.EnableMaxLen {
-		f.check_max_len = true
-		f.max_len = opts.MaxLen
-		f.s_max_len = strconv.Itoa(opts.MaxLen)
+		len_rule: StrLenRule{
+			EnableMinLen: opts.EnableMinLen,
+			MinLen:       opts.MinLen,
+			EnableMaxLen: opts.EnableMaxLen,
+			MaxLen:       opts.MaxLen,
+		},
+		op_len_rules:     opts.LenRules,
+		normalize_arabic: opts.NormalizeArabic,
+		normalized_col:   opts.NormalizedCol,
+		max_vals:         opts.MaxValues,
+		match_all:        opts.MatchAll,
+		null_opt:         opts.NullOpt,
 	}
 
 	return &f
 }
 
-func (s *str_filter) validate_and_construct(
+func (s *str_filter) ValidateAndConstruct(
 	v url.Values,
 	lang string,
+	ctx *SqlCtx,
 ) (string, error) {
+	// eq, ne, in, nin, sw, ew, ct, ieq, isw, iew, ict, null
 
-	var val string
-	var ok bool
-	var err error
+	var cond, err = s.validate_and_construct_vals(v, lang, ctx)
 
-	if val, ok = get_first_el_if_exists(v, s.key+"[eq]"); ok {
-		if err = s.does_val_exceed_max_len(val, lang); err != nil {
-			return "", err
-		}
+	if err != nil {
+		return "", err
+	}
 
-		return s.col_alias + "=" + to_escaped_string(val), nil
+	if s.null_opt {
+		cond = with_null_cond(v, s.key, ctx.Ident(s.col_alias), cond)
 	}
 
-	if val, ok = get_first_el_if_exists(v, s.key+"[null]"); ok {
-		if err = s.does_val_exceed_max_len(val, lang); err != nil {
-			return "", err
+	return cond, nil
+}
+
+func (s *str_filter) validate_and_construct_vals(
+	v url.Values,
+	lang string,
+	ctx *SqlCtx,
+) (string, error) {
+	// the conditions of the different operators are joined with AND: name[sw]=a&name[ne]=ab
+	var conds = []string{}
+
+	for _, op := range list_ops {
+		if vals, ok := get_val_if_exists(v, s.key+"["+op+"]"); ok {
+			if err := s.validate_vals(op, vals, lang); err != nil {
+				return "", err
+			}
+			conds = append(conds, s.construct_list(op, vals, ctx))
 		}
-		return s.col_alias + "=NULL", nil
 	}
 
-	if val, ok = get_first_el_if_exists(v, s.key+"[sw]"); ok {
-		if err = s.does_val_exceed_max_len(val, lang); err != nil {
-			return "", err
+	for _, op := range like_ops {
+		if vals, ok := get_val_if_exists(v, s.key+"["+op+"]"); ok {
+			if err := s.validate_vals(op, vals, lang); err != nil {
+				return "", err
+			}
+			conds = append(conds, s.construct_likes(op, vals, ctx))
 		}
-		return s.col_alias + " LIKE '" + escape_single_quote(val) + "%'", nil
 	}
 
-	if val, ok = get_first_el_if_exists(v, s.key+"[ew]"); ok {
-		if err = s.does_val_exceed_max_len(val, lang); err != nil {
-			return "", err
+	switch len(conds) {
+	case 0:
+		return "", nil
+	case 1:
+		return conds[0], nil
+	}
+
+	return "(" + strings.Join(conds, " AND ") + ")", nil
+}
+
+// construct_likes joins the conditions of the repeated values with OR (or AND when MatchAll is enabled)
+func (s *str_filter) construct_likes(op string, vals []string, ctx *SqlCtx) string {
+	var conds = make([]string, 0, len(vals))
+	for _, val := range vals {
+		conds = append(conds, s.construct_like(op, val, ctx))
+	}
+
+	if len(conds) == 1 {
+		return conds[0]
+	}
+
+	if s.match_all {
+		return "(" + strings.Join(conds, " AND ") + ")"
+	}
+	return "(" + strings.Join(conds, " OR ") + ")"
+}
+
+// list_ops compare the column with a list of values, the repeated (eq) is the same as (in)
+// and the repeated (ne) is the same as (nin)
+var list_ops = []string{"eq", "ne", "in", "nin"}
+
+func (s *str_filter) construct_list(op string, vals []string, ctx *SqlCtx) string {
+	if len(vals) == 1 {
+		switch op {
+		case "eq":
+			return s.col(ctx) + "=" + ctx.Bind(s.normalize(vals[0]))
+		case "ne":
+			return s.col(ctx) + "<>" + ctx.Bind(s.normalize(vals[0]))
 		}
-		return s.col_alias + " LIKE '%" + escape_single_quote(val) + "'", nil
 	}
 
-	if val, ok = get_first_el_if_exists(v, s.key+"[ct]"); ok {
-		if err = s.does_val_exceed_max_len(val, lang); err != nil {
-			return "", err
+	var list = make([]any, 0, len(vals))
+	for _, val := range vals {
+		list = append(list, s.normalize(val))
+	}
+
+	if op == "ne" || op == "nin" {
+		return s.col(ctx) + " NOT IN (" + ctx.BindList(list) + ")"
+	}
+	return s.col(ctx) + " IN (" + ctx.BindList(list) + ")"
+}
+
+// validate_vals validates the number of the values and the length of each one,
+// the path of the error is (op) for a single value and (op, idx) for repeated values
+func (s *str_filter) validate_vals(op string, vals []string, lang string) error {
+	if len(vals) > s.max_vals {
+		return new_err(s.key, vals, too_many_vals_err(s.max_vals), lang, op)
+	}
+
+	for idx, val := range vals {
+		var path = []any{op}
+		if len(vals) > 1 {
+			path = append(path, idx)
+		}
+
+		if err := s.validate_len(val, lang, path...); err != nil {
+			return err
 		}
-		return s.col_alias + " LIKE '%" + escape_single_quote(val) + "%'", nil
 	}
 
-	return "", nil
+	return nil
+}
+
+// like_ops are the operators that match a pattern, the (i) prefix makes the match case-insensitive
+var like_ops = []string{"sw", "ew", "ct", "ieq", "isw", "iew", "ict"}
+
+// construct_like matches the column against val literally (its wildcards are escaped)
+func (s *str_filter) construct_like(op string, val string, ctx *SqlCtx) string {
+	var pattern = ctx.dialect.EscapeLike(s.normalize(val))
+
+	var case_insensitive = op[0] == 'i'
+
+	if case_insensitive {
+		op = op[1:]
+	}
+
+	switch op {
+	case "sw":
+		pattern = pattern + "%"
+	case "ew":
+		pattern = "%" + pattern
+	case "ct":
+		pattern = "%" + pattern + "%"
+	}
+
+	if case_insensitive {
+		return ctx.dialect.ILike(s.col(ctx), ctx.Bind(pattern))
+	}
+
+	return ctx.dialect.Like(s.col(ctx), ctx.Bind(pattern))
+}
+
+// col returns the column side of the comparisons (normalized when NormalizeArabic is enabled)
+func (s *str_filter) col(ctx *SqlCtx) string {
+	if !s.normalize_arabic {
+		return ctx.Ident(s.col_alias)
+	}
+
+	if s.normalized_col != "" {
+		return ctx.Ident(s.normalized_col)
+	}
+
+	return normalize_arabic_sql(ctx.Ident(s.col_alias), ctx.dialect)
 }
 
-func (s *str_filter) does_val_exceed_max_len(v string, lang string) error {
-	if !s.check_max_len {
+func (s *str_filter) normalize(val string) string {
+	if s.normalize_arabic {
+		return normalize_arabic(val)
+	}
+	return val
+}
+
+func (s *str_filter) validate_len(v string, lang string, path ...any) error {
+	var rule, ok = s.op_len_rules[path[0].(string)]
+
+	if !ok {
+		rule = s.len_rule
+	}
+
+	if !rule.EnableMinLen && !rule.EnableMaxLen {
 		return nil
 	}
-	var l = len(v)
-	if l > s.max_len {
-		return &FilterErr{
-			Key:     s.key,
-			Value:   v,
-			Message: long_str_err(s.s_max_len, l, lang),
-		}
+
+	var l = str_len(v)
+
+	if rule.EnableMinLen && l < rule.MinLen {
+		return new_err(s.key, v, short_str_err(rule.MinLen, l), lang, path...)
 	}
+
+	if rule.EnableMaxLen && l > rule.MaxLen {
+		return new_err(s.key, v, long_str_err(rule.MaxLen, l), lang, path...)
+	}
+
 	return nil
 }
 
-func long_str_err(s_exp string, got int, lang string) string {
-	var s_got = strconv.Itoa(got)
-	if lang == "ar" {
-		return "يجب تقصير هذا النص إلى " + s_exp + " من الحروف أو أقل (أنت حاليا تستخدم " + s_got + " من الحروف)"
+// str_len counts the characters of v as the user sees them,
+// the combining marks (tashkeel, accents, ...) belong to the previous character
+func str_len(v string) int {
+	var l = 0
+	for _, r := range v {
+		if !unicode.Is(unicode.M, r) {
+			l++
+		}
 	}
+	return l
+}
+
+func long_str_err(max int, got int) message {
+	return message{code: "string.too_long", params: map[string]any{"max": max, "length": got}}
+}
+
+func short_str_err(min int, got int) message {
+	return message{code: "string.too_short", params: map[string]any{"min": min, "length": got}}
+}
 
-	return "Should shorten this text to " + s_exp + " characters (you are currently using " + s_got + " characters)"
+func too_many_vals_err(max int) message {
+	return message{code: "string.too_many_values", params: map[string]any{"max": max}}
 }
@@ -2,6 +2,8 @@ package filter_test
 
 import (
 	"net/url"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/MaSTeR2W/filter"
@@ -169,8 +171,6 @@ func TestStringFilterCT(t *testing.T) {
 
 func TestStringFilterNULL(t *testing.T) {
 	var lang = "ar"
-	var v = url.Values{}
-	v.Set("name[null]", "random")
 
 	const sql = "SELECT * FROM users "
 	var f = filter.NewFilters(filter.FilterConfigs{
@@ -178,17 +178,359 @@ func TestStringFilterNULL(t *testing.T) {
 	}, filter.NewStrFilter(filter.StrFilterOpts{
 		Key:          "name",
 		EnableMaxLen: true,
-		MaxLen:       10,
+		MaxLen:       3,
+		NullOpt:      true,
 	}))
 
-	var query string
-	var err error
+	var cases = []struct {
+		vals  url.Values
+		query string
+	}{
+		// the value of null is not checked against MaxLen
+		{url.Values{"name[null]": []string{"random"}}, sql + " WHERE name IS NULL"},
+		{url.Values{"name[null]": []string{"0"}}, sql + " WHERE name IS NOT NULL"},
+		{url.Values{"name[null]": []string{"1"}, "name[sw]": []string{"ab"}}, sql + " WHERE (name LIKE 'ab%' OR name IS NULL)"},
+	}
 
-	if query, err = f.ValidateAndConstruct(v, lang); err != nil {
+	for _, c := range cases {
+		var query, err = f.ValidateAndConstruct(c.vals, lang)
+
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if query != c.query {
+			t.Error("invalid query:", query)
+		}
+	}
+
+	// without NullOpt
+	f = filter.NewFilters(filter.FilterConfigs{
+		SqlSelect: sql,
+	}, filter.NewStrFilter(filter.StrFilterOpts{
+		Key: "name",
+	}))
+
+	var query, err = f.ValidateAndConstruct(url.Values{"name[null]": []string{"1"}}, lang)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if query != sql {
+		t.Error("invalid query:", query)
+	}
+}
+
+func TestStringFilterEscapeLike(t *testing.T) {
+	var lang = "ar"
+	var v = url.Values{}
+	v.Set("name[ct]", `50%_a\b`)
+
+	const sql = "SELECT * FROM users "
+
+	var cases = []struct {
+		dialect filter.Dialect
+		query   string
+	}{
+		{nil, sql + ` WHERE name LIKE '%50\%\_a\\b%'`},
+		{filter.Postgres, sql + ` WHERE name LIKE '%50\%\_a\\b%'`},
+		{filter.SQLite, sql + ` WHERE name LIKE '%50\%\_a\\b%' ESCAPE '\'`},
+		{filter.SQLServer, sql + ` WHERE name LIKE N'%50\%\_a\\b%' ESCAPE '\'`},
+	}
+
+	for _, c := range cases {
+		var f = filter.NewFilters(filter.FilterConfigs{
+			SqlSelect: sql,
+			Dialect:   c.dialect,
+		}, filter.NewStrFilter(filter.StrFilterOpts{
+			Key: "name",
+		}))
+
+		var query, err = f.ValidateAndConstruct(v, lang)
+
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if query != c.query {
+			t.Error("invalid query:", query)
+		}
+	}
+
+	// [ starts a character range in SQL Server
+	v = url.Values{}
+	v.Set("name[sw]", "[a]")
+
+	var f = filter.NewFilters(filter.FilterConfigs{
+		SqlSelect: sql,
+		Dialect:   filter.SQLServer,
+	}, filter.NewStrFilter(filter.StrFilterOpts{
+		Key: "name",
+	}))
+
+	var query, args, err = f.ValidateAndConstructWithArgs(v, lang)
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if query != sql+` WHERE name LIKE @p1 ESCAPE '\'` || len(args) != 1 || args[0] != `\[a]%` {
+		t.Error("invalid query:", query, args)
+	}
+}
+
+func TestStringFilterCaseInsensitive(t *testing.T) {
+	var lang = "ar"
+
+	const sql = "SELECT * FROM users "
+
+	var cases = []struct {
+		op      string
+		dialect filter.Dialect
+		query   string
+	}{
+		{"ieq", nil, sql + " WHERE LOWER(name) LIKE LOWER('Ra\\_m')"},
+		{"isw", nil, sql + " WHERE LOWER(name) LIKE LOWER('Ra\\_m%')"},
+		{"iew", filter.Postgres, sql + " WHERE name ILIKE '%Ra\\_m'"},
+		{"ict", filter.Postgres, sql + " WHERE name ILIKE '%Ra\\_m%'"},
+		{"ict", filter.MySQL, sql + " WHERE LOWER(name) LIKE LOWER('%Ra\\\\_m%')"},
+		{"isw", filter.SQLite, sql + " WHERE LOWER(name) LIKE LOWER('Ra\\_m%') ESCAPE '\\'"},
+	}
+
+	for _, c := range cases {
+		var v = url.Values{}
+		v.Set("name["+c.op+"]", "Ra_m")
+
+		var f = filter.NewFilters(filter.FilterConfigs{
+			SqlSelect: sql,
+			Dialect:   c.dialect,
+		}, filter.NewStrFilter(filter.StrFilterOpts{
+			Key: "name",
+		}))
+
+		var query, err = f.ValidateAndConstruct(v, lang)
+
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if query != c.query {
+			t.Error("invalid query:", c.op, query)
+		}
+	}
+}
+
+func TestStringFilterNormalizeArabic(t *testing.T) {
+	var lang = "ar"
+
+	const sql = "SELECT * FROM users "
+
+	var new_filters = func(normalized_col string) interface {
+		ValidateAndConstructWithArgs(url.Values, string) (string, []any, error)
+	} {
+		return filter.NewFilters(filter.FilterConfigs{
+			SqlSelect: sql,
+			Dialect:   filter.Postgres,
+		}, filter.NewStrFilter(filter.StrFilterOpts{
+			Key:             "name",
+			NormalizeArabic: true,
+			NormalizedCol:   normalized_col,
+		}))
+	}
+
+	var cases = []struct {
+		op    string
+		input string
+		query string
+		arg   string
+	}{
+		{"ct", "أَحْمَـد", sql + " WHERE name_normalized LIKE $1", "%احمد%"},
+		{"eq", "إيمان", sql + " WHERE name_normalized=$1", "ايمان"},
+		{"sw", "آمنة", sql + " WHERE name_normalized LIKE $1", "امنه%"},
+		{"iew", "مصطفى", sql + " WHERE name_normalized ILIKE $1", "%مصطفي"},
+	}
+
+	var fs = new_filters("name_normalized")
+
+	for _, c := range cases {
+		var v = url.Values{}
+		v.Set("name["+c.op+"]", c.input)
+
+		var query, args, err = fs.ValidateAndConstructWithArgs(v, lang)
+
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if query != c.query || len(args) != 1 || args[0] != c.arg {
+			t.Error("invalid query:", query, args)
+		}
+	}
+
+	var v = url.Values{}
+	v.Set("name[ct]", "احمد")
+
+	// SQL expression
+	var query, _, err = new_filters("normalize_ar(name)").ValidateAndConstructWithArgs(v, lang)
+
+	if err != nil {
 		t.Error(err)
+		return
 	}
 
-	if query != sql+" WHERE name=NULL" {
+	if query != sql+" WHERE normalize_ar(name) LIKE $1" {
 		t.Error("invalid query:", query)
 	}
+
+	// nested REPLACE calls
+	query, _, err = new_filters("").ValidateAndConstructWithArgs(v, lang)
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !strings.HasPrefix(query, sql+" WHERE REPLACE(REPLACE(") ||
+		!strings.Contains(query, "REPLACE(name,'أ','ا'),'إ','ا')") ||
+		!strings.HasSuffix(query, ",'ـ','') LIKE $1") {
+		t.Error("invalid query:", query)
+	}
+}
+
+func TestStringFilterLength(t *testing.T) {
+	const sql = "SELECT * FROM users "
+
+	var f = filter.NewFilters(filter.FilterConfigs{
+		SqlSelect: sql,
+	}, filter.NewStrFilter(filter.StrFilterOpts{
+		Key:          "name",
+		EnableMinLen: true,
+		MinLen:       2,
+		EnableMaxLen: true,
+		MaxLen:       5,
+		LenRules: map[string]filter.StrLenRule{
+			"ct": {EnableMinLen: true, MinLen: 3},
+		},
+	}))
+
+	var cases = []struct {
+		op    string
+		input string
+		lang  string
+		msg   string
+	}{
+		// 5 letters (10 bytes) with tashkeel
+		{"eq", "مُحَمَّدٌ", "ar", ""},
+		{"eq", "أحمد علي", "ar", "يجب تقصير هذا النص إلى 5 من الحروف أو أقل (أنت حاليا تستخدم 8 من الحروف)"},
+		{"sw", "a", "en", "Should lengthen this text to 2 characters or more (you are currently using 1 characters)"},
+		{"ct", "ab", "en", "Should lengthen this text to 3 characters or more (you are currently using 2 characters)"},
+		// no max length for ct
+		{"ct", "abcdefgh", "en", ""},
+		{"ew", "é", "en", "Should lengthen this text to 2 characters or more (you are currently using 1 characters)"},
+	}
+
+	for _, c := range cases {
+		var v = url.Values{}
+		v.Set("name["+c.op+"]", c.input)
+
+		var _, err = f.ValidateAndConstruct(v, c.lang)
+
+		if c.msg == "" {
+			if err != nil {
+				t.Error(c.input, err)
+			}
+			continue
+		}
+
+		if err == nil {
+			t.Error("should throw error:", c.input)
+			continue
+		}
+
+		var f_err = (*err.(*filter.FilterErrs))[0].(*filter.FilterErr)
+
+		if f_err.Message != c.msg || len(f_err.Path) != 1 || f_err.Path[0] != c.op {
+			t.Error("invalid error:", c.input, f_err)
+		}
+	}
+}
+
+func TestStringFilterMultipleValues(t *testing.T) {
+	const sql = "SELECT * FROM users "
+
+	var new_filters = func(match_all bool) interface {
+		ValidateAndConstruct(url.Values, string) (string, error)
+	} {
+		return filter.NewFilters(filter.FilterConfigs{
+			SqlSelect: sql,
+		}, filter.NewStrFilter(filter.StrFilterOpts{
+			Key:          "name",
+			EnableMaxLen: true,
+			MaxLen:       5,
+			MaxValues:    3,
+			MatchAll:     match_all,
+		}))
+	}
+
+	var cases = []struct {
+		vals      url.Values
+		match_all bool
+		query     string
+	}{
+		{url.Values{"name[eq]": []string{"a", "b"}}, false, sql + " WHERE name IN ('a','b')"},
+		{url.Values{"name[ne]": []string{"a"}}, false, sql + " WHERE name<>'a'"},
+		{url.Values{"name[ne]": []string{"a", "b"}}, false, sql + " WHERE name NOT IN ('a','b')"},
+		{url.Values{"name[in]": []string{"a"}}, false, sql + " WHERE name IN ('a')"},
+		{url.Values{"name[nin]": []string{"a", "b", "c"}}, false, sql + " WHERE name NOT IN ('a','b','c')"},
+		{url.Values{"name[ct]": []string{"a", "b"}}, false, sql + " WHERE (name LIKE '%a%' OR name LIKE '%b%')"},
+		{url.Values{"name[sw]": []string{"a", "b"}}, true, sql + " WHERE (name LIKE 'a%' AND name LIKE 'b%')"},
+		// the different operators are joined with AND
+		{url.Values{"name[eq]": []string{"a"}, "name[ct]": []string{"b"}}, false, sql + " WHERE (name='a' AND name LIKE '%b%')"},
+		{url.Values{"name[ne]": []string{"ab"}, "name[sw]": []string{"a", "c"}}, false, sql + " WHERE (name<>'ab' AND (name LIKE 'a%' OR name LIKE 'c%'))"},
+		{url.Values{"name[in]": []string{"a", "b"}, "name[ict]": []string{"x"}}, false, sql + " WHERE (name IN ('a','b') AND LOWER(name) LIKE LOWER('%x%'))"},
+	}
+
+	for _, c := range cases {
+		var query, err = new_filters(c.match_all).ValidateAndConstruct(c.vals, "en")
+
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if query != c.query {
+			t.Error("invalid query:", query)
+		}
+	}
+
+	var errs = []struct {
+		vals url.Values
+		path []any
+		msg  string
+	}{
+		{url.Values{"name[in]": []string{"a", "b", "c", "d"}}, []any{"in"}, "Cannot enter more than 3 values"},
+		{url.Values{"name[ct]": []string{"a", "abcdef"}}, []any{"ct", 1}, "Should shorten this text to 5 characters (you are currently using 6 characters)"},
+		{url.Values{"name[eq]": []string{"abcdef"}}, []any{"eq"}, "Should shorten this text to 5 characters (you are currently using 6 characters)"},
+	}
+
+	for _, c := range errs {
+		var _, err = new_filters(false).ValidateAndConstruct(c.vals, "en")
+
+		if err == nil {
+			t.Error("should throw error:", c.vals)
+			continue
+		}
+
+		var f_err = (*err.(*filter.FilterErrs))[0].(*filter.FilterErr)
+
+		if f_err.Message != c.msg || !reflect.DeepEqual(f_err.Path, c.path) {
+			t.Error("invalid error:", f_err)
+		}
+	}
 }
@@ -1,12 +1,58 @@
 package filter_test
 
 import (
+	"encoding/json"
+	"errors"
 	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/MaSTeR2W/filter"
 )
 
+// filters users whose age is within [min, max] from a single `age[between]=min,max` value
+type between_filter struct {
+	key string
+}
+
+func (b *between_filter) ValidateAndConstruct(v url.Values, lang string, ctx *filter.SqlCtx) (string, error) {
+	var val, ok = filter.FirstValue(v, b.key+"[between]")
+
+	if !ok {
+		return "", nil
+	}
+
+	var bounds = strings.Split(val, ",")
+	if len(bounds) != 2 {
+		return "", filter.NewFilterErr(b.key, val, "invalid range", "between")
+	}
+
+	var min, err_min = strconv.Atoi(bounds[0])
+	var max, err_max = strconv.Atoi(bounds[1])
+
+	if err_min != nil || err_max != nil {
+		return "", filter.NewFilterErr(b.key, val, "invalid range", "between")
+	}
+
+	return ctx.Ident(b.key) + " BETWEEN " + ctx.Bind(min) + " AND " + ctx.Bind(max), nil
+}
+
+// binds the configured values (of any type) when its key exists
+type bind_filter struct {
+	key  string
+	vals []any
+}
+
+func (b *bind_filter) ValidateAndConstruct(v url.Values, lang string, ctx *filter.SqlCtx) (string, error) {
+	if _, ok := filter.FirstValue(v, b.key+"[in]"); !ok {
+		return "", nil
+	}
+
+	return ctx.Ident(b.key) + " IN (" + ctx.BindList(b.vals) + ")", nil
+}
+
 func TestFilter(t *testing.T) {
 	var fs = filter.NewFilters(filter.FilterConfigs{
 		SqlSelect: "SELECT * FROM users",
@@ -45,4 +91,349 @@ func TestFilter(t *testing.T) {
 	}
 
 	t.Run("test_query_count", test_query_count)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_query_count_args = func(t *testing.T) {
+		var v = url.Values{
+			"firstName[eq]": []string{"mar'wan"},
+			"$order_by":     []string{"lastName"},
+			"$limit":        []string{"10"},
+			"$page":         []string{"5"},
+		}
+
+		var sel, sel_args, count, count_args, err = fs.ValidateAndConstructWithCountAndArgs(v, "ar")
+
+		if err != nil {
+			t.Error("error should be nil:", err)
+			return
+		}
+
+		if sel != "SELECT * FROM users WHERE firstName=? ORDER BY lastName ASC LIMIT ? OFFSET ?" {
+			t.Error("invalid sel:", sel)
+		}
+
+		if !reflect.DeepEqual(sel_args, []any{"mar'wan", 10, 40}) {
+			t.Error("invalid sel args:", sel_args)
+		}
+
+		if count != "SELECT COUNT(*) AS count FROM users WHERE firstName=?" {
+			t.Error("invalid count:", count)
+		}
+
+		if !reflect.DeepEqual(count_args, []any{"mar'wan"}) {
+			t.Error("invalid count args:", count_args)
+		}
+	}
+
+	t.Run("test_query_count_args", test_query_count_args)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_dollar_placeholder = func(t *testing.T) {
+		var fs = filter.NewFilters(filter.FilterConfigs{
+			SqlSelect:   "SELECT * FROM users",
+			Paginate:    true,
+			Placeholder: filter.DollarPlaceholder,
+		}, filter.NewStrFilter(filter.StrFilterOpts{
+			Key: "firstName",
+		}), filter.NewCheckboxIntFilter(filter.CheckboxIntFilterOpts{
+			Key:  "status",
+			Opts: []int{1, 2, 3},
+		}))
+
+		var v = url.Values{
+			"firstName[ct]": []string{"mar"},
+			"status[in]":    []string{"1", "3"},
+			"$limit":        []string{"10"},
+			"$page":         []string{"2"},
+		}
+
+		var sel, args, err = fs.ValidateAndConstructWithArgs(v, "ar")
+
+		if err != nil {
+			t.Error("error should be nil:", err)
+			return
+		}
+
+		if sel != "SELECT * FROM users WHERE firstName LIKE $1 AND status IN ($2,$3) LIMIT $4 OFFSET $5" {
+			t.Error("invalid sel:", sel)
+		}
+
+		if !reflect.DeepEqual(args, []any{"%mar%", 1, 3, 10, 10}) {
+			t.Error("invalid args:", args)
+		}
+	}
+
+	t.Run("test_dollar_placeholder", test_dollar_placeholder)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_custom_filter = func(t *testing.T) {
+		var fs = filter.NewFilters(filter.FilterConfigs{
+			SqlSelect:   "SELECT * FROM users",
+			Placeholder: filter.DollarPlaceholder,
+		}, filter.NewStrFilter(filter.StrFilterOpts{
+			Key: "firstName",
+		}), &between_filter{key: "age"})
+
+		var v = url.Values{
+			"firstName[eq]": []string{"marwan"},
+			"age[between]":  []string{"18,30"},
+		}
+
+		var sel, args, err = fs.ValidateAndConstructWithArgs(v, "en")
+
+		if err != nil {
+			t.Error("error should be nil:", err)
+			return
+		}
+
+		if sel != "SELECT * FROM users WHERE firstName=$1 AND age BETWEEN $2 AND $3" {
+			t.Error("invalid sel:", sel)
+		}
+
+		if !reflect.DeepEqual(args, []any{"marwan", 18, 30}) {
+			t.Error("invalid args:", args)
+		}
+
+		v = url.Values{
+			"age[between]": []string{"18"},
+		}
+
+		_, _, err = fs.ValidateAndConstructWithArgs(v, "en")
+
+		if err == nil {
+			t.Error("should throw error")
+			return
+		}
+
+		var f_err = (*err.(*filter.FilterErrs))[0].(*filter.FilterErr)
+
+		if f_err.Key != "age" || f_err.Value != "18" || f_err.Path[0] != "between" {
+			t.Error("invalid error:", f_err)
+		}
+	}
+
+	t.Run("test_custom_filter", test_custom_filter)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_bind_kinds = func(t *testing.T) {
+		type status int8
+
+		var n = int64(7)
+
+		var fs = filter.NewFilters(filter.FilterConfigs{
+			SqlSelect: "SELECT * FROM users",
+		}, &bind_filter{key: "code", vals: []any{
+			int64(5), uint(6), &n, int32(-3), float32(1.5), status(2), nil, (*int)(nil), []byte("x'y"),
+		}})
+
+		var v = url.Values{
+			"code[in]": []string{"1"},
+		}
+
+		var sel, err = fs.ValidateAndConstruct(v, "en")
+
+		if err != nil {
+			t.Error("error should be nil:", err)
+			return
+		}
+
+		if sel != "SELECT * FROM users WHERE code IN (5,6,7,-3,1.5,2,NULL,NULL,'x''y')" {
+			t.Error("invalid sel:", sel)
+		}
+	}
+
+	t.Run("test_bind_kinds", test_bind_kinds)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_err_codes = func(t *testing.T) {
+		var fs = filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect: "SELECT * FROM users",
+				Paginate:  true,
+				LimitMax:  10,
+				OrderBy:   []string{"firstName", "lastName"},
+			},
+			filter.NewIntFilter(filter.IntFilterOpts{
+				Key:       "age",
+				EnableMin: true,
+				Min:       18,
+			}),
+			filter.NewCheckboxStrFilter(filter.CheckboxStrFilterOpts{
+				Key:  "status",
+				Opts: []string{"active", "banned"},
+			}),
+		)
+
+		var v = url.Values{
+			"age[eq]":    []string{"12"},
+			"status[in]": []string{"deleted"},
+			"$order_by":  []string{"email"},
+			"$page":      []string{"1"},
+			"$limit":     []string{"20"},
+		}
+
+		var _, err = fs.ValidateAndConstruct(v, "en")
+
+		if err == nil {
+			t.Error("should throw error")
+			return
+		}
+
+		var errs = *err.(*filter.FilterErrs)
+
+		var expected = []struct {
+			code   string
+			params map[string]any
+		}{
+			{"number.too_small", map[string]any{"min": 18}},
+			{"option.not_allowed", map[string]any{"options": []string{"active", "banned"}}},
+			{"sort.not_allowed", map[string]any{"columns": []string{"firstName", "lastName"}}},
+			{"limit.exceeds_max", map[string]any{"max": 10}},
+		}
+
+		if len(errs) != len(expected) {
+			t.Error("invalid errors:", errs)
+			return
+		}
+
+		for idx, e := range expected {
+			var f_err = errs[idx].(*filter.FilterErr)
+
+			if f_err.Code != e.code || !reflect.DeepEqual(f_err.Params, e.params) {
+				t.Error("invalid error:", f_err.Code, f_err.Params)
+			}
+		}
+
+		var js, _ = json.Marshal(errs[0])
+
+		if string(js) != `{"key":"age","value":"12","path":[],"code":"number.too_small","params":{"min":18},"message":"The number should be greater than or equal to 18"}` {
+			t.Error("invalid json:", string(js))
+		}
+
+		// the custom errors do not have a code
+		js, _ = json.Marshal(filter.NewFilterErr("age", "18", "invalid range", "between"))
+
+		if string(js) != `{"key":"age","value":"18","path":["between"],"message":"invalid range"}` {
+			t.Error("invalid json:", string(js))
+		}
+
+		// the coded custom errors are translated from the catalog
+		var c = filter.NewMessageCatalog()
+
+		c.Register("en", map[string]string{
+			"range.invalid": "The range should be (min,max) starting from {min}",
+		})
+
+		filter.SetCatalog(c)
+		defer filter.SetCatalog(filter.DefaultCatalog)
+
+		var f_err = filter.NewCodedFilterErr("age", "18", "range.invalid", map[string]any{"min": 0}, "en-US", "between")
+
+		js, _ = json.Marshal(f_err)
+
+		if string(js) != `{"key":"age","value":"18","path":["between"],"code":"range.invalid","params":{"min":0},"message":"The range should be (min,max) starting from 0"}` {
+			t.Error("invalid json:", string(js))
+		}
+	}
+
+	t.Run("test_err_codes", test_err_codes)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_err_json = func(t *testing.T) {
+		var errs = filter.FilterErrs{
+			&filter.FilterErr{
+				Key:     `na"me\`,
+				Value:   "line1\nline2\t\x01",
+				Path:    []any{"$or", 1, 2.5, true},
+				Code:    "string.too_long",
+				Params:  map[string]any{"max": 3, "length": 12},
+				Message: `use "less" \ shorter`,
+			},
+			&filter.FilterErr{
+				Key:     "$limit",
+				Value:   filter.OmitVal,
+				Message: "The limit is missing",
+			},
+			errors.New("custom"),
+		}
+
+		var js, err = json.Marshal(errs)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if !json.Valid(js) {
+			t.Error("invalid json:", string(js))
+			return
+		}
+
+		var expected = `{"errors":[` +
+			`{"key":"na\"me\\","value":"line1\nline2\t\u0001","path":["$or",1,2.5,true],"code":"string.too_long","params":{"length":12,"max":3},"message":"use \"less\" \\ shorter"},` +
+			`{"key":"$limit","path":[],"message":"The limit is missing"},` +
+			`{"path":[],"message":"custom"}]}`
+
+		if string(js) != expected {
+			t.Error("invalid json:", string(js))
+		}
+
+		var decoded filter.FilterErrs
+
+		if err = json.Unmarshal(js, &decoded); err != nil {
+			t.Error(err)
+			return
+		}
+
+		errs[2] = &filter.FilterErr{Value: filter.OmitVal, Message: "custom"}
+
+		if len(decoded) != len(errs) {
+			t.Error("invalid decoded errors:", decoded)
+			return
+		}
+
+		for idx := range errs {
+			if !reflect.DeepEqual(decoded[idx], errs[idx]) {
+				t.Error("invalid decoded error:", decoded[idx], errs[idx])
+			}
+		}
+	}
+
+	t.Run("test_err_json", test_err_json)
 }
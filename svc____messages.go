@@ -0,0 +1,95 @@
+package filter
+
+// the message ids are stable (they can be overridden by RegisterMessages),
+// the parameters of each message are written between braces
+
+var builtin_messages = map[string]map[string]string{
+	"ar": {
+		"bool.invalid": "يجب أن تكون القيمة نعم أو لا",
+
+		"number.invalid":                  "عدد غير صالح",
+		"number.too_small":                "يجب أن يكون العدد أكبر من أو يساوي {min}",
+		"number.too_large":                "يجب أن يكون العدد أصغر من أو يساوي {max}",
+		"number.too_many_fraction_digits": "يجب ألا يتجاوز عدد الخانات بعد الفاصلة العشرية {scale}",
+		"number.too_many_integer_digits":  "يجب ألا يتجاوز عدد الخانات قبل الفاصلة العشرية {digits}",
+
+		"string.too_long":        "يجب تقصير هذا النص إلى {max} من الحروف أو أقل (أنت حاليا تستخدم {length} من الحروف)",
+		"string.too_short":       "يجب إطالة هذا النص إلى {min} من الحروف أو أكثر (أنت حاليا تستخدم {length} من الحروف)",
+		"string.too_many_values": "لا يمكن إدخال أكثر من {max} من القيم",
+
+		"option.too_many":           "لا يمكن تجاوز عدد الخيارات المتاحة ({max})",
+		"option.not_a_number":       "هذا ليس عددا",
+		"option.number_not_allowed": "يجب أن يكون العدد واحد من: ({options})",
+		"option.not_allowed":        "يجب أن يكون الخيار واحد من: ({options})",
+
+		"date.invalid":        "التاريخ غير صالح",
+		"date.too_early":      "يجب أن يكون التاريخ بعد ({after}), التاريخ الذي أدخلته ({input})",
+		"date.too_late":       "يجب أن يكون التاريخ قبل ({before}), التاريخ الذي أدخلته ({input})",
+		"date.invalid_preset": "يجب أن تكون الفترة إحدى القيم ({presets})",
+		"date.hijri_marker":   "هـ",
+
+		"group.invalid":             "صيغة المجموعة غير صالحة",
+		"group.too_deep":            "لا يمكن أن يتجاوز تداخل المجموعات {max} مستويات",
+		"group.too_many_conditions": "لا يمكن أن يتجاوز عدد الشروط داخل المجموعات {max}",
+
+		"sort.not_allowed":   "يجب اختيار واحد مما يلي: ({columns})",
+		"sort.too_many_keys": "لا يمكن الترتيب بأكثر من {max} أعمدة",
+		"sort.duplicate_key": "لا يمكن تكرار العمود ({column})",
+
+		"page.invalid":      "رقم الصفحة غير صالح",
+		"page.missing":      "رقم الصفحة مفقود",
+		"limit.invalid":     "الحد غير صالح",
+		"limit.missing":     "الحد مفقود",
+		"limit.too_small":   "يجب أن يكون الحد {min} على الأقل",
+		"limit.exceeds_max": "يجب ألا يتجاوز الحد {max}",
+
+		"cursor.invalid": "المؤشر غير صالح",
+
+		"problem.title":  "معاملات الاستعلام غير صالحة",
+		"problem.detail": "عدد الأخطاء في معاملات الاستعلام: {count}",
+	},
+	"en": {
+		"bool.invalid": "The value should be true or false",
+
+		"number.invalid":                  "invalid number",
+		"number.too_small":                "The number should be greater than or equal to {min}",
+		"number.too_large":                "The number should be less than or equal to {max}",
+		"number.too_many_fraction_digits": "The number should not have more than {scale} digits after the decimal point",
+		"number.too_many_integer_digits":  "The number should not have more than {digits} digits before the decimal point",
+
+		"string.too_long":        "Should shorten this text to {max} characters (you are currently using {length} characters)",
+		"string.too_short":       "Should lengthen this text to {min} characters or more (you are currently using {length} characters)",
+		"string.too_many_values": "Cannot enter more than {max} values",
+
+		"option.too_many":           "The number of options available ({max}) cannot be exceeded",
+		"option.not_a_number":       "This is not a number",
+		"option.number_not_allowed": "The number should be one of: ({options})",
+		"option.not_allowed":        "The option should be one of: ({options})",
+
+		"date.invalid":        "The date is invalid",
+		"date.too_early":      "The date should be after ({after}), the date you entered ({input})",
+		"date.too_late":       "The date should be before ({before}), the date you entered ({input})",
+		"date.invalid_preset": "The preset should be one of ({presets})",
+		"date.hijri_marker":   " AH",
+
+		"group.invalid":             "The group syntax is invalid",
+		"group.too_deep":            "Groups cannot be nested more than {max} levels",
+		"group.too_many_conditions": "The number of conditions inside groups cannot exceed {max}",
+
+		"sort.not_allowed":   "Should select one of the following: ({columns})",
+		"sort.too_many_keys": "Cannot sort by more than {max} columns",
+		"sort.duplicate_key": "The column ({column}) cannot be repeated",
+
+		"page.invalid":      "Page number is invalid",
+		"page.missing":      "Page number is missing",
+		"limit.invalid":     "The limit is invalid",
+		"limit.missing":     "The limit is missing",
+		"limit.too_small":   "The limit should be at least {min}",
+		"limit.exceeds_max": "The limit should not exceed {max}",
+
+		"cursor.invalid": "The cursor is invalid",
+
+		"problem.title":  "Invalid query parameters",
+		"problem.detail": "The query parameters have {count} error(s)",
+	},
+}
@@ -0,0 +1,127 @@
+package filter
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 document of the validation errors of a query
+type Problem struct {
+	Type   string       `json:"type"`
+	Title  string       `json:"title"`
+	Status int          `json:"status"`
+	Detail string       `json:"detail,omitempty"`
+	Errors []ProblemErr `json:"errors"`
+}
+
+// ProblemErr is a single validation error of Problem (the extension member "errors")
+type ProblemErr struct {
+	Key     string `json:"key,omitempty"`
+	Path    []any  `json:"path"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// NewProblem converts the error of ValidateAndConstruct (*FilterErrs, *FilterErr or any other error)
+// to a problem document, the title, the detail and the messages of the codes are translated to lang
+// (with Eastern Arabic digits for the Arabic languages when the errors come from filters with FilterConfigs.ArabicDigits)
+func NewProblem(err error, lang string) *Problem {
+	var errs FilterErrs
+
+	switch t := err.(type) {
+	case nil:
+	case *FilterErrs:
+		if t != nil {
+			errs = *t
+		}
+	default:
+		errs = FilterErrs{err}
+	}
+
+	var p = Problem{
+		Type:   "about:blank",
+		Title:  translate(lang, "problem.title", nil),
+		Status: http.StatusBadRequest,
+		Detail: translate(lang, "problem.detail", map[string]any{"count": len(errs)}),
+		Errors: make([]ProblemErr, 0, len(errs)),
+	}
+
+	// the Arabic messages are written in Eastern Arabic digits
+	var ar = primary_lang(lang) == "ar"
+	var ar_digits = false
+
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		var f_err, ok = err.(*FilterErr)
+
+		if ok && f_err == nil {
+			continue
+		}
+
+		if !ok {
+			p.Errors = append(p.Errors, ProblemErr{
+				Path:    []any{},
+				Message: err.Error(),
+			})
+			continue
+		}
+
+		var p_err = ProblemErr{
+			Key:     f_err.Key,
+			Path:    f_err.Path,
+			Code:    f_err.Code,
+			Message: f_err.Message,
+		}
+
+		if p_err.Path == nil {
+			p_err.Path = []any{}
+		}
+
+		ar_digits = ar_digits || (ar && f_err.ar_digits)
+
+		// the error may be created in another language
+		if f_err.Code != "" {
+			p_err.Message = translate(lang, f_err.Code, f_err.Params)
+
+			if ar && f_err.ar_digits {
+				p_err.Message = localize_digits(p_err.Message)
+			}
+		}
+
+		p.Errors = append(p.Errors, p_err)
+	}
+
+	if ar_digits {
+		p.Detail = localize_digits(p.Detail)
+	}
+
+	return &p
+}
+
+// WriteProblem writes the problem document of err with its status (400 Bad Request)
+func WriteProblem(w http.ResponseWriter, err error, lang string) error {
+	var p = NewProblem(err, lang)
+
+	var js, js_err = json.Marshal(p)
+
+	if js_err != nil {
+		return js_err
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+
+	if lang != "" {
+		w.Header().Set("Content-Language", lang)
+	}
+
+	w.WriteHeader(p.Status)
+
+	_, js_err = w.Write(js)
+
+	return js_err
+}
@@ -0,0 +1,190 @@
+package filter_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/MaSTeR2W/filter"
+)
+
+func TestProblem(t *testing.T) {
+	var fs = filter.NewFilters(
+		filter.FilterConfigs{
+			SqlSelect: "SELECT * FROM users",
+			Paginate:  true,
+		},
+		filter.NewIntFilter(filter.IntFilterOpts{
+			Key:       "age",
+			EnableMin: true,
+			Min:       18,
+		}),
+	)
+
+	var v = url.Values{
+		"age[eq]": []string{"12"},
+		"$page":   []string{"1"},
+	}
+
+	var test_write = func(t *testing.T) {
+		var _, err = fs.ValidateAndConstruct(v, "en")
+
+		if err == nil {
+			t.Error("should throw error")
+			return
+		}
+
+		var rec = httptest.NewRecorder()
+
+		if err = filter.WriteProblem(rec, err, "en"); err != nil {
+			t.Error(err)
+			return
+		}
+
+		if rec.Code != http.StatusBadRequest {
+			t.Error("invalid status:", rec.Code)
+		}
+
+		if rec.Header().Get("Content-Type") != "application/problem+json" || rec.Header().Get("Content-Language") != "en" {
+			t.Error("invalid headers:", rec.Header())
+		}
+
+		var expected = `{"type":"about:blank","title":"Invalid query parameters","status":400,"detail":"The query parameters have 2 error(s)","errors":[` +
+			`{"key":"age","path":[],"code":"number.too_small","message":"The number should be greater than or equal to 18"},` +
+			`{"key":"$limit","path":[],"code":"limit.missing","message":"The limit is missing"}]}`
+
+		if rec.Body.String() != expected {
+			t.Error("invalid body:", rec.Body.String())
+		}
+	}
+
+	t.Run("test_write", test_write)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_localized = func(t *testing.T) {
+		// validated in English, reported in Arabic
+		var _, err = fs.ValidateAndConstruct(v, "en")
+
+		var p = filter.NewProblem(err, "ar-SA")
+
+		if p.Title != "معاملات الاستعلام غير صالحة" || p.Detail != "عدد الأخطاء في معاملات الاستعلام: 2" {
+			t.Error("invalid problem:", p.Title, p.Detail)
+		}
+
+		if len(p.Errors) != 2 || p.Errors[0].Message != "يجب أن يكون العدد أكبر من أو يساوي 18" {
+			t.Error("invalid errors:", p.Errors)
+		}
+	}
+
+	t.Run("test_localized", test_localized)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_other_errors = func(t *testing.T) {
+		var p = filter.NewProblem(filter.NewFilterErr("age", "x", "invalid range", "between"), "en")
+
+		if len(p.Errors) != 1 || p.Errors[0].Key != "age" || p.Errors[0].Path[0] != "between" || p.Errors[0].Message != "invalid range" {
+			t.Error("invalid errors:", p.Errors)
+		}
+
+		p = filter.NewProblem(errors.New("boom"), "en")
+
+		if len(p.Errors) != 1 || p.Errors[0].Message != "boom" || p.Detail != "The query parameters have 1 error(s)" {
+			t.Error("invalid problem:", p)
+		}
+	}
+
+	t.Run("test_other_errors", test_other_errors)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_arabic_digits = func(t *testing.T) {
+		var fs = filter.NewFilters(
+			filter.FilterConfigs{
+				SqlSelect:    "SELECT * FROM users",
+				ArabicDigits: true,
+			},
+			filter.NewIntFilter(filter.IntFilterOpts{
+				Key:       "age",
+				EnableMin: true,
+				Min:       18,
+			}),
+		)
+
+		var _, err = fs.ValidateAndConstruct(url.Values{"age[eq]": []string{"12"}}, "ar")
+
+		var p = filter.NewProblem(err, "ar")
+
+		if p.Detail != "عدد الأخطاء في معاملات الاستعلام: ١" || p.Errors[0].Message != "يجب أن يكون العدد أكبر من أو يساوي ١٨" {
+			t.Error("invalid problem:", p.Detail, p.Errors)
+		}
+
+		// another language
+		p = filter.NewProblem(err, "en")
+
+		if p.Errors[0].Message != "The number should be greater than or equal to 18" {
+			t.Error("invalid errors:", p.Errors)
+		}
+
+		// validated in English, reported in Arabic
+		_, err = fs.ValidateAndConstruct(url.Values{"age[eq]": []string{"12"}}, "en")
+
+		p = filter.NewProblem(err, "ar")
+
+		if p.Detail != "عدد الأخطاء في معاملات الاستعلام: ١" || p.Errors[0].Message != "يجب أن يكون العدد أكبر من أو يساوي ١٨" {
+			t.Error("invalid problem:", p.Detail, p.Errors)
+		}
+
+		// the messages without numbers
+		_, err = fs.ValidateAndConstruct(url.Values{"age[eq]": []string{"abc"}}, "ar")
+
+		p = filter.NewProblem(err, "ar")
+
+		if p.Detail != "عدد الأخطاء في معاملات الاستعلام: ١" {
+			t.Error("invalid problem:", p.Detail)
+		}
+	}
+
+	t.Run("test_arabic_digits", test_arabic_digits)
+
+	//
+	//
+	//
+	//
+	//
+	//
+
+	var test_nil = func(t *testing.T) {
+		var p = filter.NewProblem(nil, "en")
+
+		if len(p.Errors) != 0 || p.Status != http.StatusBadRequest {
+			t.Error("invalid problem:", p)
+		}
+
+		p = filter.NewProblem((*filter.FilterErrs)(nil), "en")
+
+		if len(p.Errors) != 0 {
+			t.Error("invalid problem:", p)
+		}
+	}
+
+	t.Run("test_nil", test_nil)
+}